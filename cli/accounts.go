@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/KharpukhaevV/gitui/config"
+	"github.com/KharpukhaevV/gitui/models"
+)
+
+// accountJSON аккаунт в выводе команд. Токен никогда не выводится.
+type accountJSON struct {
+	Name         string   `json:"name"`
+	Login        string   `json:"login,omitempty"`
+	Host         string   `json:"host"`
+	APIURL       string   `json:"api_url,omitempty"`
+	Protocol     string   `json:"protocol"`
+	TokenBackend string   `json:"token_backend"`
+	Scopes       []string `json:"scopes,omitempty"`
+	GitName      string   `json:"git_name,omitempty"`
+	GitEmail     string   `json:"git_email,omitempty"`
+	SigningKey   string   `json:"signing_key,omitempty"`
+	SSHKey       string   `json:"ssh_key,omitempty"`
+	SSHHost      string   `json:"ssh_host,omitempty"`
+	Private      bool     `json:"private"`
+	Created      string   `json:"created"`
+}
+
+// newAccountJSON преобразует аккаунт для вывода
+func newAccountJSON(account models.Account) accountJSON {
+	backend := account.TokenBackend
+	if backend == "" {
+		backend = config.BackendFile
+	}
+	return accountJSON{
+		Name:         account.Name,
+		Login:        account.Login,
+		Host:         account.WebHost(),
+		APIURL:       account.APIURL,
+		Protocol:     account.Protocol(),
+		TokenBackend: backend,
+		Scopes:       account.Scopes,
+		GitName:      account.GitName,
+		GitEmail:     account.GitEmail,
+		SigningKey:   account.SigningKey,
+		SSHKey:       account.SSHKey,
+		SSHHost:      account.SSHHost,
+		Private:      account.Private,
+		Created:      account.Created.Format(time.RFC3339),
+	}
+}
+
+// accounts выполняет команды управления аккаунтами
+func (a *app) accounts(args []string) error {
+	if len(args) == 0 {
+		return usagef("accounts: expected list, add or remove")
+	}
+	switch args[0] {
+	case "list":
+		return a.accountsList(args[1:])
+	case "add":
+		return a.accountsAdd(args[1:])
+	case "remove":
+		return a.accountsRemove(args[1:])
+	}
+	return usagef("accounts: unknown command %q", args[0])
+}
+
+// accountsList выводит список аккаунтов
+func (a *app) accountsList(args []string) error {
+	fs := a.flagSet("accounts list")
+	asJSON := fs.Bool("json", false, "print JSON")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usagef("accounts list: unexpected argument %q", fs.Arg(0))
+	}
+
+	accounts, err := a.loadAccounts()
+	if err != nil {
+		return err
+	}
+	return a.writeAccounts(accounts, *asJSON)
+}
+
+// writeAccounts выводит аккаунты в формате JSON или TSV
+func (a *app) writeAccounts(accounts []models.Account, asJSON bool) error {
+	if asJSON {
+		result := make([]accountJSON, 0, len(accounts))
+		for _, account := range accounts {
+			result = append(result, newAccountJSON(account))
+		}
+		return a.writeJSON(result)
+	}
+	for _, account := range accounts {
+		out := newAccountJSON(account)
+		a.writeTSV(out.Name, out.Login, out.Host, out.Protocol, out.TokenBackend)
+	}
+	return nil
+}
+
+// accountsAdd проверяет токен и добавляет аккаунт
+func (a *app) accountsAdd(args []string) error {
+	fs := a.flagSet("accounts add")
+	name := fs.String("name", "", "account name")
+	token := fs.String("token", "", "token, reference like env:GITHUB_TOKEN, or - to read from stdin")
+	apiURL := fs.String("api-url", "", "GitHub Enterprise Server API URL")
+	protocol := fs.String("protocol", "", "default clone protocol: https or ssh")
+	gitName := fs.String("git-name", "", "git user.name for cloned repositories")
+	gitEmail := fs.String("git-email", "", "git user.email for cloned repositories")
+	signingKey := fs.String("signing-key", "", "GPG key ID or SSH .pub key for signing commits")
+	sshKey := fs.String("ssh-key", "", "private SSH key for cloning over SSH")
+	sshHost := fs.String("ssh-host", "", "host alias from ~/.ssh/config used in SSH clone URLs")
+	asJSON := fs.Bool("json", false, "print JSON")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	switch {
+	case fs.NArg() > 0:
+		return usagef("accounts add: unexpected argument %q", fs.Arg(0))
+	case strings.TrimSpace(*name) == "":
+		return usagef("accounts add: --name is required")
+	case *token == "":
+		return usagef("accounts add: --token is required")
+	case *protocol != "" && *protocol != models.ProtocolHTTPS && *protocol != models.ProtocolSSH:
+		return usagef("accounts add: unknown protocol %q", *protocol)
+	}
+
+	accounts, err := a.loadAccounts()
+	if err != nil {
+		return err
+	}
+	if _, err := findAccount(accounts, *name); err == nil {
+		return fmt.Errorf("account %s already exists", strings.TrimSpace(*name))
+	}
+
+	account := models.Account{
+		Name:          strings.TrimSpace(*name),
+		APIURL:        strings.TrimSpace(*apiURL),
+		CloneProtocol: *protocol,
+		GitName:       strings.TrimSpace(*gitName),
+		GitEmail:      strings.TrimSpace(*gitEmail),
+		SigningKey:    strings.TrimSpace(*signingKey),
+		SSHKey:        strings.TrimSpace(*sshKey),
+		SSHHost:       strings.TrimSpace(*sshHost),
+		Created:       time.Now(),
+	}
+	if err := a.setToken(&account, *token); err != nil {
+		return err
+	}
+
+	validated := a.client.ValidateAccount(a.ctx, 0, account)().(models.AccountValidatedMsg)
+	if validated.Err != nil {
+		return fmt.Errorf("invalid token: %v", validated.Err)
+	}
+	account = validated.Account
+
+	if err := a.saveAccounts(append(accounts, account)); err != nil {
+		return fmt.Errorf("error saving accounts: %v", err)
+	}
+	if account.Scopes != nil && !account.HasScope("repo") {
+		fmt.Fprintf(a.stderr, "gitui: warning: the token lacks the repo scope: private repositories cannot be cloned\n")
+	}
+	return a.writeAccounts([]models.Account{account}, *asJSON)
+}
+
+// setToken подставляет токен в аккаунт и создает клиент GitHub. Значение может быть
+// ссылкой на внешнее хранилище (например, "env:GITHUB_TOKEN") или "-" для чтения из stdin.
+func (a *app) setToken(account *models.Account, value string) error {
+	if value == "-" {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("error reading token from stdin: %v", err)
+		}
+		value = strings.TrimSpace(line)
+		if value == "" {
+			return errors.New("empty token on stdin")
+		}
+	}
+
+	account.Token = value
+	if backend, ref, ok := a.config.ParseTokenRef(value); ok {
+		token, err := a.config.ResolveToken(backend, ref)
+		if err != nil {
+			return fmt.Errorf("error reading token from %s: %v", backend, err)
+		}
+		account.Token = token
+		account.TokenBackend = backend
+		account.TokenRef = ref
+	}
+	if err := account.Connect(); err != nil {
+		return fmt.Errorf("error connecting to %s: %v", account.APIURL, err)
+	}
+	return nil
+}
+
+// accountsRemove удаляет аккаунт по имени
+func (a *app) accountsRemove(args []string) error {
+	fs := a.flagSet("accounts remove")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("accounts remove: expected one account name")
+	}
+
+	accounts, err := a.loadAccounts()
+	if err != nil {
+		return err
+	}
+	account, err := findAccount(accounts, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var rest []models.Account
+	for _, acc := range accounts {
+		if acc.Name != account.Name {
+			rest = append(rest, acc)
+		}
+	}
+	if rest == nil {
+		rest = []models.Account{}
+	}
+	if err := a.saveAccounts(rest); err != nil {
+		return fmt.Errorf("error saving accounts: %v", err)
+	}
+	// Кэш и токен удаленного аккаунта больше не нужны
+	a.client.ForgetAccount(account)
+	if err := a.config.DeleteToken(account, rest); err != nil {
+		fmt.Fprintf(a.stderr, "gitui: %v\n", err)
+	}
+	fmt.Fprintf(a.stderr, "Account %s removed\n", account.Name)
+	return nil
+}
@@ -0,0 +1,287 @@
+// Package cli реализует неинтерактивные команды gitui для скриптов.
+// Команды используют ту же конфигурацию и тот же клиент GitHub, что и TUI.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/KharpukhaevV/gitui/config"
+	githubClient "github.com/KharpukhaevV/gitui/github"
+	"github.com/KharpukhaevV/gitui/models"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
+)
+
+// Коды завершения команд
+const (
+	// ExitOK команда выполнена успешно
+	ExitOK = 0
+	// ExitFailure команда завершилась ошибкой (в том числе частичной)
+	ExitFailure = 1
+	// ExitUsage неверные аргументы команды
+	ExitUsage = 2
+)
+
+// passphraseEnv переменная окружения с парольной фразой для неинтерактивного запуска
+const passphraseEnv = "GITUI_PASSPHRASE"
+
+// usage справка по командам
+const usage = `Usage:
+  gitui                                   start the interactive UI
+  gitui accounts list [--json]
+  gitui accounts add --name NAME --token TOKEN|REF|- [--api-url URL] [--protocol https|ssh]
+                     [--ssh-key PATH] [--ssh-host ALIAS] [--git-name NAME] [--git-email EMAIL]
+                     [--signing-key KEY] [--json]
+  gitui accounts remove NAME
+  gitui repos list [--account NAME] [--owner LOGIN] [--filter QUERY] [--sort KEY] [--desc] [--json]
+  gitui clone [--account NAME] [--ssh|--https] [--json] OWNER/NAME...
+  gitui sync [--account NAME] [--pull] [--json]
+  gitui ratelimit [--account NAME] [--json]
+
+Without --account, repos, sync and ratelimit use all accounts, and clone uses the first account with access.
+The passphrase is read from $GITUI_PASSPHRASE or asked in the terminal.
+`
+
+// usageError ошибка в аргументах команды
+type usageError struct {
+	msg string
+}
+
+// Error возвращает текст ошибки
+func (e usageError) Error() string {
+	return e.msg
+}
+
+// usagef создает ошибку в аргументах команды
+func usagef(format string, args ...interface{}) error {
+	return usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// errPartial сообщает, что часть операций не выполнена; подробности уже выведены
+var errPartial = errors.New("some operations failed")
+
+// app окружение выполнения команды
+type app struct {
+	ctx    context.Context
+	stdout io.Writer
+	stderr io.Writer
+	config *config.Manager
+	client *githubClient.Client
+}
+
+// Run выполняет команду с аргументами args и возвращает код завершения
+func Run(args []string, stdout, stderr io.Writer) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	configManager := config.NewManager()
+	a := &app{
+		ctx:    ctx,
+		stdout: stdout,
+		stderr: stderr,
+		config: configManager,
+		client: githubClient.NewClient(configManager.Settings()),
+	}
+
+	err := a.run(args)
+	var usageErr usageError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, flag.ErrHelp):
+		fmt.Fprint(stdout, usage)
+		return ExitOK
+	case errors.As(err, &usageErr):
+		fmt.Fprintf(stderr, "gitui: %v\nRun 'gitui help' for usage.\n", err)
+		return ExitUsage
+	case errors.Is(err, errPartial):
+		return ExitFailure
+	}
+	fmt.Fprintf(stderr, "gitui: %v\n", err)
+	return ExitFailure
+}
+
+// run выбирает команду по первому аргументу
+func (a *app) run(args []string) error {
+	if len(args) == 0 {
+		return usagef("expected a command")
+	}
+	switch args[0] {
+	case "accounts":
+		return a.accounts(args[1:])
+	case "repos":
+		return a.repos(args[1:])
+	case "clone":
+		return a.clone(args[1:])
+	case "sync":
+		return a.sync(args[1:])
+	case "ratelimit":
+		return a.ratelimit(args[1:])
+	case "help", "-h", "--help":
+		fmt.Fprint(a.stdout, usage)
+		return nil
+	}
+	return usagef("unknown command %q", args[0])
+}
+
+// flagSet создает набор флагов команды. Ошибки разбора и справку выводит Run,
+// поэтому сам набор флагов ничего не печатает.
+func (a *app) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {}
+	return fs
+}
+
+// parse разбирает флаги команды. Ошибки разбора считаются ошибками аргументов.
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{msg: err.Error()}
+	}
+	return nil
+}
+
+// passphrase получает парольную фразу из переменной окружения или из терминала.
+// confirm запрашивает повторный ввод для новой парольной фразы.
+func (a *app) passphrase(confirm bool) (string, error) {
+	if value, ok := os.LookupEnv(passphraseEnv); ok {
+		return value, nil
+	}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("passphrase required: set %s or run in a terminal", passphraseEnv)
+	}
+
+	fmt.Fprint(a.stderr, "Passphrase: ")
+	value, err := term.ReadPassword(fd)
+	fmt.Fprintln(a.stderr)
+	if err != nil {
+		return "", err
+	}
+	if confirm {
+		fmt.Fprint(a.stderr, "Repeat passphrase: ")
+		repeat, err := term.ReadPassword(fd)
+		fmt.Fprintln(a.stderr)
+		if err != nil {
+			return "", err
+		}
+		if string(repeat) != string(value) {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+	return string(value), nil
+}
+
+// loadAccounts загружает аккаунты, при необходимости запрашивая парольную фразу.
+// Ошибки отдельных аккаунтов выводятся как предупреждения: такие аккаунты остаются без клиента.
+func (a *app) loadAccounts() ([]models.Account, error) {
+	if a.config.NeedsUnlock() {
+		passphrase, err := a.passphrase(false)
+		if err != nil {
+			return nil, err
+		}
+		if err := a.config.Unlock(passphrase); err != nil {
+			return nil, err
+		}
+	}
+
+	accounts, err := a.config.LoadAccounts()
+	if accounts == nil {
+		return nil, err
+	}
+	if err != nil {
+		fmt.Fprintf(a.stderr, "gitui: warning: %v\n", err)
+	}
+	return accounts, nil
+}
+
+// saveAccounts сохраняет аккаунты. Если для шифрования токена еще нет
+// парольной фразы, она запрашивается.
+func (a *app) saveAccounts(accounts []models.Account) error {
+	err := a.config.SaveAccounts(accounts)
+	if !errors.Is(err, config.ErrLocked) {
+		return err
+	}
+	passphrase, err := a.passphrase(!a.config.HasPassphrase())
+	if err != nil {
+		return err
+	}
+	if err := a.config.Unlock(passphrase); err != nil {
+		return err
+	}
+	return a.config.SaveAccounts(accounts)
+}
+
+// findAccount ищет аккаунт по имени без учета регистра
+func findAccount(accounts []models.Account, name string) (models.Account, error) {
+	for _, account := range accounts {
+		if strings.EqualFold(account.Name, name) {
+			return account, nil
+		}
+	}
+	return models.Account{}, usagef("unknown account %q", name)
+}
+
+// selectAccounts возвращает указанный аккаунт или все аккаунты, если имя пустое
+func selectAccounts(accounts []models.Account, name string) ([]models.Account, error) {
+	if name == "" {
+		if len(accounts) == 0 {
+			return nil, errors.New("no accounts configured")
+		}
+		return accounts, nil
+	}
+	account, err := findAccount(accounts, name)
+	if err != nil {
+		return nil, err
+	}
+	return []models.Account{account}, nil
+}
+
+// wait передает сообщения задачи в handle до ее завершения.
+// При прерывании команды (Ctrl+C) задача отменяется.
+func (a *app) wait(job *githubClient.Job, handle func(tea.Msg)) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-a.ctx.Done():
+			job.Cancel()
+		case <-done:
+		}
+	}()
+
+	for {
+		msg := job.Wait()()
+		if msg == nil {
+			return
+		}
+		handle(msg)
+	}
+}
+
+// writeJSON выводит значение в формате JSON
+func (a *app) writeJSON(value interface{}) error {
+	encoder := json.NewEncoder(a.stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// writeTSV выводит строку значений, разделенных табуляцией
+func (a *app) writeTSV(fields ...string) {
+	for i, field := range fields {
+		// Табуляция и перевод строки внутри значения сломали бы разбор строки
+		fields[i] = strings.NewReplacer("\t", " ", "\n", " ").Replace(field)
+	}
+	fmt.Fprintln(a.stdout, strings.Join(fields, "\t"))
+}
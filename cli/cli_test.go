@@ -0,0 +1,347 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KharpukhaevV/gitui/cli"
+	"github.com/KharpukhaevV/gitui/github/githubtest"
+	"github.com/KharpukhaevV/gitui/models"
+)
+
+// tokenEnv переменная окружения, из которой аккаунты тестов получают токен
+const tokenEnv = "GITUI_TEST_TOKEN"
+
+// setupHome создает временную домашнюю директорию с файлом конфигурации.
+// Токены аккаунтов берутся из переменной окружения, поэтому парольная фраза не нужна.
+func setupHome(t *testing.T, accounts ...models.Account) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+	t.Setenv(tokenEnv, githubtest.Token)
+
+	for i := range accounts {
+		if accounts[i].TokenBackend == "" {
+			accounts[i].TokenBackend = "env"
+			accounts[i].TokenRef = tokenEnv
+		}
+		if accounts[i].Created.IsZero() {
+			accounts[i].Created = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		}
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"version":  2,
+		"settings": models.Settings{CloneRoot: filepath.Join(home, "develop")},
+		"accounts": accounts,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".github_manager.json"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return home
+}
+
+// run выполняет команду и возвращает код завершения и вывод
+func run(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := cli.Run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// decodeList разбирает JSON-массив объектов из вывода команды
+func decodeList(t *testing.T, output string) []map[string]interface{} {
+	t.Helper()
+	var list []map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &list); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, output)
+	}
+	return list
+}
+
+// keys возвращает отсортированные ключи объекта
+func keys(object map[string]interface{}) []string {
+	var result []string
+	for key := range object {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestUsageErrors(t *testing.T) {
+	tests := [][]string{
+		{},
+		{"bogus"},
+		{"accounts"},
+		{"accounts", "rename"},
+		{"accounts", "list", "--bogus"},
+		{"accounts", "list", "extra"},
+		{"accounts", "add", "--token", "x"},
+		{"accounts", "add", "--name", "work"},
+		{"accounts", "add", "--name", "work", "--token", "x", "--protocol", "ftp"},
+		{"accounts", "remove"},
+		{"accounts", "remove", "missing"},
+		{"repos", "list", "--sort", "bogus"},
+		{"repos", "list", "--filter", "stars:>"},
+		{"repos", "list", "--account", "missing"},
+		{"clone"},
+		{"clone", "--ssh", "--https", "octocat/hello"},
+		{"clone", "octocat"},
+		{"sync", "extra"},
+	}
+
+	setupHome(t, models.Account{Name: "work", APIURL: "http://127.0.0.1:1"})
+	for _, args := range tests {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			code, stdout, stderr := run(t, args...)
+			if code != cli.ExitUsage {
+				t.Errorf("exit code = %d, want %d\nstderr: %s", code, cli.ExitUsage, stderr)
+			}
+			if stdout != "" {
+				t.Errorf("unexpected output: %s", stdout)
+			}
+			if !strings.HasPrefix(stderr, "gitui: ") || !strings.HasSuffix(stderr, "\nRun 'gitui help' for usage.\n") || strings.Count(stderr, "\n") != 2 {
+				t.Errorf("stderr = %q", stderr)
+			}
+		})
+	}
+}
+
+func TestHelp(t *testing.T) {
+	setupHome(t)
+	for _, args := range [][]string{{"help"}, {"--help"}, {"repos", "list", "-h"}} {
+		code, stdout, _ := run(t, args...)
+		if code != cli.ExitOK || !strings.HasPrefix(stdout, "Usage:") {
+			t.Errorf("%v: exit code = %d, stdout = %q", args, code, stdout)
+		}
+	}
+}
+
+func TestAccountsList(t *testing.T) {
+	setupHome(t,
+		models.Account{Name: "work", Login: "octocat", APIURL: "https://ghe.example.com/api/v3", Scopes: []string{"repo"}, Private: true},
+		models.Account{Name: "personal", CloneProtocol: models.ProtocolSSH},
+	)
+
+	code, stdout, stderr := run(t, "accounts", "list", "--json")
+	if code != cli.ExitOK {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr)
+	}
+	list := decodeList(t, stdout)
+	if len(list) != 2 {
+		t.Fatalf("got %d accounts, want 2", len(list))
+	}
+
+	want := []string{"api_url", "created", "host", "login", "name", "private", "protocol", "scopes", "token_backend"}
+	if got := keys(list[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("account keys = %q, want %q", got, want)
+	}
+	work := list[0]
+	if work["name"] != "work" || work["login"] != "octocat" || work["host"] != "ghe.example.com" ||
+		work["protocol"] != "https" || work["token_backend"] != "env" || work["private"] != true ||
+		work["created"] != "2024-01-01T00:00:00Z" {
+		t.Errorf("work = %v", work)
+	}
+	personal := list[1]
+	if personal["host"] != "github.com" || personal["protocol"] != "ssh" {
+		t.Errorf("personal = %v", personal)
+	}
+	if strings.Contains(stdout, githubtest.Token) {
+		t.Error("token printed in the account list")
+	}
+
+	code, stdout, _ = run(t, "accounts", "list")
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if code != cli.ExitOK || len(lines) != 2 || lines[0] != "work\toctocat\tghe.example.com\thttps\tenv" {
+		t.Errorf("exit code = %d, TSV output:\n%s", code, stdout)
+	}
+}
+
+func TestAccountsListEmpty(t *testing.T) {
+	setupHome(t)
+	code, stdout, stderr := run(t, "accounts", "list", "--json")
+	if code != cli.ExitOK || strings.TrimSpace(stdout) != "[]" {
+		t.Errorf("exit code = %d, stdout = %q, stderr = %q", code, stdout, stderr)
+	}
+}
+
+func TestAccountsAddRemove(t *testing.T) {
+	fake := githubtest.NewServer(t)
+	home := setupHome(t)
+
+	code, stdout, stderr := run(t, "accounts", "add", "--name", "work", "--token", "env:"+tokenEnv, "--api-url", fake.URL,
+		"--ssh-key", "~/.ssh/id_work", "--ssh-host", "github-work",
+		"--git-name", "Jane Doe", "--git-email", "jane@acme.example", "--signing-key", "ABCDEF", "--json")
+	if code != cli.ExitOK {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr)
+	}
+	// Добавленный аккаунт выводится в том же формате, что и список
+	list := decodeList(t, stdout)
+	if len(list) != 1 {
+		t.Fatalf("got %d accounts, want 1", len(list))
+	}
+	added := list[0]
+	if added["name"] != "work" || added["login"] != "octocat" || added["token_backend"] != "env" ||
+		added["git_name"] != "Jane Doe" || added["git_email"] != "jane@acme.example" || added["signing_key"] != "ABCDEF" ||
+		added["ssh_key"] != "~/.ssh/id_work" || added["ssh_host"] != "github-work" {
+		t.Errorf("added = %v", added)
+	}
+
+	config, err := os.ReadFile(filepath.Join(home, ".github_manager.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(config), githubtest.Token) {
+		t.Error("token stored in the config instead of the reference")
+	}
+
+	if code, _, stderr := run(t, "accounts", "add", "--name", "WORK", "--token", "env:"+tokenEnv, "--api-url", fake.URL); code != cli.ExitFailure || !strings.Contains(stderr, "already exists") {
+		t.Errorf("duplicate add: exit code = %d, stderr = %q", code, stderr)
+	}
+
+	// Загрузка списка создает кэш аккаунта, который удаляется вместе с ним
+	if code, _, stderr := run(t, "repos", "list", "--account", "work"); code != cli.ExitOK {
+		t.Fatalf("repos list: exit code = %d, stderr = %q", code, stderr)
+	}
+	cacheDir := filepath.Join(home, ".cache", "gitui", "repos", "work")
+	if _, err := os.Stat(cacheDir); err != nil {
+		t.Fatalf("cache not written: %v", err)
+	}
+
+	if code, _, stderr := run(t, "accounts", "remove", "work"); code != cli.ExitOK {
+		t.Fatalf("remove: exit code = %d, stderr = %q", code, stderr)
+	}
+	if _, stdout, _ := run(t, "accounts", "list", "--json"); strings.TrimSpace(stdout) != "[]" {
+		t.Errorf("accounts after remove = %s", stdout)
+	}
+	if _, err := os.Stat(cacheDir); !os.IsNotExist(err) {
+		t.Errorf("cache left after remove: %v", err)
+	}
+}
+
+func TestReposList(t *testing.T) {
+	fake := githubtest.NewServer(t,
+		[]map[string]interface{}{githubtest.Repo("octocat", "hello"), githubtest.Repo("acme", "api")},
+		[]map[string]interface{}{githubtest.Repo("acme", "cli")},
+	)
+	setupHome(t, models.Account{Name: "work", APIURL: fake.URL})
+
+	code, stdout, stderr := run(t, "repos", "list", "--account", "work", "--sort", "name", "--json")
+	if code != cli.ExitOK {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr)
+	}
+	list := decodeList(t, stdout)
+	var names []string
+	for _, repo := range list {
+		names = append(names, repo["full_name"].(string))
+	}
+	if want := []string{"acme/api", "acme/cli", "octocat/hello"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("repositories = %q, want %q", names, want)
+	}
+
+	want := []string{
+		"accounts", "archived", "clone_url", "description", "fork", "forks", "full_name", "host", "language",
+		"license", "name", "open_issues", "owner", "private", "size_kb", "ssh_url", "stars", "topics", "updated_at", "web_url",
+	}
+	if got := keys(list[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("repository keys = %q, want %q", got, want)
+	}
+	repo := list[0]
+	if repo["owner"] != "acme" || repo["name"] != "api" || repo["private"] != true || repo["stars"] != float64(3) ||
+		repo["language"] != "Go" || repo["license"] != "MIT" || repo["updated_at"] != "2024-05-01T10:00:00Z" ||
+		repo["clone_url"] != "https://github.example.com/acme/api.git" {
+		t.Errorf("repository = %v", repo)
+	}
+	if accounts, _ := repo["accounts"].([]interface{}); len(accounts) != 1 || accounts[0] != "work" {
+		t.Errorf("accounts = %v, want [work]", repo["accounts"])
+	}
+
+	code, stdout, _ = run(t, "repos", "list", "--filter", "owner:acme cli")
+	if fields := strings.Split(strings.TrimSpace(stdout), "\t"); code != cli.ExitOK || len(fields) != 8 || fields[0] != "acme/cli" || fields[7] != "work" {
+		t.Errorf("exit code = %d, TSV output:\n%s", code, stdout)
+	}
+}
+
+func TestReposListPartialFailure(t *testing.T) {
+	work := githubtest.NewServer(t, []map[string]interface{}{githubtest.Repo("acme", "api")})
+	broken := githubtest.NewServer(t)
+	broken.Fail(http.StatusUnauthorized, nil)
+	setupHome(t,
+		models.Account{Name: "work", APIURL: work.URL},
+		models.Account{Name: "expired", APIURL: broken.URL},
+	)
+
+	// Репозитории доступных аккаунтов выводятся, но код завершения сообщает об ошибке
+	code, stdout, stderr := run(t, "repos", "list", "--json")
+	if code != cli.ExitFailure {
+		t.Errorf("exit code = %d, want %d", code, cli.ExitFailure)
+	}
+	if list := decodeList(t, stdout); len(list) != 1 || list[0]["full_name"] != "acme/api" {
+		t.Errorf("repositories = %v", list)
+	}
+	if !strings.Contains(stderr, "expired") {
+		t.Errorf("stderr = %q, want the failed account", stderr)
+	}
+
+	// Ошибка единственного аккаунта
+	code, stdout, stderr = run(t, "repos", "list", "--account", "expired", "--json")
+	if code != cli.ExitFailure || stdout != "" || !strings.HasPrefix(stderr, "gitui: ") {
+		t.Errorf("exit code = %d, stdout = %q, stderr = %q", code, stdout, stderr)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	fake := githubtest.NewServer(t)
+	setupHome(t,
+		models.Account{Name: "work", APIURL: fake.URL},
+		models.Account{Name: "offline", APIURL: "http://127.0.0.1:1"},
+	)
+	reset := githubtest.RateReset.Format(time.RFC3339)
+
+	code, stdout, stderr := run(t, "ratelimit", "--account", "work", "--json")
+	if code != cli.ExitOK {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr)
+	}
+	list := decodeList(t, stdout)
+	if len(list) != 1 || list[0]["account"] != "work" {
+		t.Fatalf("ratelimit = %v", list)
+	}
+	core, _ := list[0]["core"].(map[string]interface{})
+	if core["limit"] != 5000.0 || core["remaining"] != 4990.0 || core["reset"] != reset {
+		t.Errorf("core = %v", core)
+	}
+
+	code, stdout, _ = run(t, "ratelimit", "--account", "work")
+	if want := "work\tcore\t4990\t5000\t" + reset + "\nwork\tsearch\t30\t30\t" + reset + "\n"; code != cli.ExitOK || stdout != want {
+		t.Errorf("exit code = %d, TSV output:\n%s", code, stdout)
+	}
+
+	// Лимиты доступных аккаунтов выводятся, но код завершения сообщает об ошибке
+	code, stdout, stderr = run(t, "ratelimit", "--json")
+	if code != cli.ExitFailure || !strings.Contains(stderr, "offline") {
+		t.Errorf("exit code = %d, stderr = %q", code, stderr)
+	}
+	if list := decodeList(t, stdout); len(list) != 2 || list[1]["error"] == nil || list[1]["core"] != nil {
+		t.Errorf("ratelimit = %v", list)
+	}
+}
+
+func TestNoAccounts(t *testing.T) {
+	setupHome(t)
+	code, _, stderr := run(t, "repos", "list")
+	if code != cli.ExitFailure || !strings.Contains(stderr, "no accounts configured") {
+		t.Errorf("exit code = %d, stderr = %q", code, stderr)
+	}
+}
@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	githubClient "github.com/KharpukhaevV/gitui/github"
+	"github.com/KharpukhaevV/gitui/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// resultJSON результат клонирования или синхронизации репозитория в выводе команд
+type resultJSON struct {
+	FullName string `json:"full_name"`
+	Account  string `json:"account"`
+	Action   string `json:"action"`
+	Status   string `json:"status"`
+	Path     string `json:"path,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Статусы результата
+const (
+	statusDone     = "ok"
+	statusCloned   = "cloned"
+	statusExists   = "exists"
+	statusFailed   = "failed"
+	statusCanceled = "canceled"
+)
+
+// newResultJSON преобразует результат очереди для вывода
+func newResultJSON(msg models.CloneMsg) resultJSON {
+	out := resultJSON{
+		FullName: msg.Repo.Title(),
+		Account:  msg.Account,
+		Action:   msg.Action,
+		Path:     msg.Path,
+	}
+	switch {
+	case msg.Canceled:
+		out.Status = statusCanceled
+	case msg.Existing:
+		out.Status = statusExists
+	case msg.Success && msg.Action == models.ActionClone:
+		out.Status = statusCloned
+	case msg.Success:
+		out.Status = statusDone
+	default:
+		out.Status = statusFailed
+	}
+	// При успешном клонировании ошибка означает, что не удалось применить git identity
+	if msg.Err != nil && !msg.Canceled {
+		out.Error = msg.Err.Error()
+	}
+	return out
+}
+
+// clone клонирует репозитории, указанные как OWNER/NAME
+func (a *app) clone(args []string) error {
+	fs := a.flagSet("clone")
+	accountName := fs.String("account", "", "account name (default: first account with access)")
+	ssh := fs.Bool("ssh", false, "clone over SSH")
+	https := fs.Bool("https", false, "clone over HTTPS with the account token")
+	asJSON := fs.Bool("json", false, "print JSON")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return usagef("clone: expected OWNER/NAME")
+	}
+	if *ssh && *https {
+		return usagef("clone: --ssh and --https are mutually exclusive")
+	}
+	protocol := ""
+	if *ssh {
+		protocol = models.ProtocolSSH
+	} else if *https {
+		protocol = models.ProtocolHTTPS
+	}
+
+	all, err := a.loadAccounts()
+	if err != nil {
+		return err
+	}
+	accounts, err := selectAccounts(all, *accountName)
+	if err != nil {
+		return err
+	}
+
+	var tasks []githubClient.CloneTask
+	var results []resultJSON
+	for _, arg := range fs.Args() {
+		owner, name, ok := strings.Cut(arg, "/")
+		if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return usagef("clone: expected OWNER/NAME, got %q", arg)
+		}
+		repo, account, err := a.findRepo(accounts, owner, name)
+		if err != nil {
+			results = append(results, resultJSON{FullName: arg, Action: models.ActionClone, Status: statusFailed, Error: err.Error()})
+			continue
+		}
+		tasks = append(tasks, githubClient.CloneTask{Repo: repo, Account: account, Protocol: protocol})
+	}
+
+	return a.runQueue(tasks, results, *asJSON)
+}
+
+// findRepo ищет репозиторий среди аккаунтов и возвращает первый аккаунт с доступом к нему
+func (a *app) findRepo(accounts []models.Account, owner, name string) (models.Repository, models.Account, error) {
+	var errs []error
+	for _, account := range accounts {
+		repo, err := a.client.GetRepo(a.ctx, account, owner, name)
+		if err == nil {
+			return repo, account, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %v", account.Name, err))
+	}
+	return models.Repository{}, models.Account{}, errors.Join(errs...)
+}
+
+// sync выполняет fetch или pull всех склонированных репозиториев аккаунта
+func (a *app) sync(args []string) error {
+	fs := a.flagSet("sync")
+	accountName := fs.String("account", "", "account name (default: all accounts)")
+	pull := fs.Bool("pull", false, "fast-forward pull instead of fetch")
+	asJSON := fs.Bool("json", false, "print JSON")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usagef("sync: unexpected argument %q", fs.Arg(0))
+	}
+	action := models.ActionFetch
+	if *pull {
+		action = models.ActionPull
+	}
+
+	all, err := a.loadAccounts()
+	if err != nil {
+		return err
+	}
+	accounts, err := selectAccounts(all, *accountName)
+	if err != nil {
+		return err
+	}
+
+	repos, loadErr := a.loadRepos(accounts)
+	if repos == nil && loadErr != nil {
+		return loadErr
+	}
+
+	// Каждый репозиторий синхронизируется через первый аккаунт с доступом к нему
+	var tasks []githubClient.CloneTask
+	for _, repo := range repos {
+		account, ok := repo.AccountFor(accounts)
+		if !ok {
+			continue
+		}
+		if len(a.client.ClonedRepos([]models.Repository{repo}, account)) == 0 {
+			continue
+		}
+		tasks = append(tasks, githubClient.CloneTask{Repo: repo, Account: account, Action: action})
+	}
+
+	if err := a.runQueue(tasks, nil, *asJSON); err != nil {
+		return err
+	}
+	return loadErr
+}
+
+// runQueue выполняет задачи очереди и выводит результаты вместе с уже известными
+// результатами results. Если хотя бы одна задача не выполнена, возвращается errPartial.
+func (a *app) runQueue(tasks []githubClient.CloneTask, results []resultJSON, asJSON bool) error {
+	if len(tasks) > 0 {
+		a.wait(a.client.CloneQueue(tasks), func(msg tea.Msg) {
+			if done, ok := msg.(models.CloneMsg); ok {
+				results = append(results, newResultJSON(done))
+			}
+		})
+	}
+
+	failed := false
+	for _, result := range results {
+		if result.Status == statusFailed || result.Status == statusCanceled {
+			failed = true
+		}
+	}
+
+	if asJSON {
+		if results == nil {
+			results = []resultJSON{}
+		}
+		if err := a.writeJSON(results); err != nil {
+			return err
+		}
+	} else {
+		for _, result := range results {
+			a.writeTSV(result.FullName, result.Status, result.Path, result.Error)
+		}
+	}
+
+	if a.ctx.Err() != nil {
+		return a.ctx.Err()
+	}
+	if failed {
+		return errPartial
+	}
+	return nil
+}
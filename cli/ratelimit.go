@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/KharpukhaevV/gitui/models"
+)
+
+// rateJSON лимит запросов одного вида в выводе команд
+type rateJSON struct {
+	Limit     int    `json:"limit"`
+	Remaining int    `json:"remaining"`
+	Reset     string `json:"reset"`
+}
+
+// rateLimitJSON лимиты запросов аккаунта в выводе команд
+type rateLimitJSON struct {
+	Account string    `json:"account"`
+	Core    *rateJSON `json:"core,omitempty"`
+	Search  *rateJSON `json:"search,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// newRateJSON преобразует лимит для вывода. Неизвестный лимит не выводится.
+func newRateJSON(rate models.RateLimit) *rateJSON {
+	if !rate.Known() {
+		return nil
+	}
+	return &rateJSON{Limit: rate.Limit, Remaining: rate.Remaining, Reset: rate.Reset.Format(time.RFC3339)}
+}
+
+// ratelimit выводит текущие лимиты запросов к API аккаунтов.
+// Запрос /rate_limit сам лимит не расходует.
+func (a *app) ratelimit(args []string) error {
+	fs := a.flagSet("ratelimit")
+	accountName := fs.String("account", "", "account name (default: all accounts)")
+	asJSON := fs.Bool("json", false, "print JSON")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usagef("ratelimit: unexpected argument %q", fs.Arg(0))
+	}
+
+	all, err := a.loadAccounts()
+	if err != nil {
+		return err
+	}
+	accounts, err := selectAccounts(all, *accountName)
+	if err != nil {
+		return err
+	}
+
+	var result []rateLimitJSON
+	failed := false
+	for _, account := range accounts {
+		msg := a.client.RateLimits(a.ctx, account)().(models.RateLimitMsg)
+		out := rateLimitJSON{Account: account.Name, Core: newRateJSON(msg.Core), Search: newRateJSON(msg.Search)}
+		if msg.Err != nil {
+			if a.ctx.Err() != nil {
+				return a.ctx.Err()
+			}
+			out.Error = msg.Err.Error()
+			failed = true
+			fmt.Fprintf(a.stderr, "gitui: %s: %v\n", account.Name, msg.Err)
+		}
+		result = append(result, out)
+	}
+
+	if *asJSON {
+		if err := a.writeJSON(result); err != nil {
+			return err
+		}
+	} else {
+		for _, out := range result {
+			for _, resource := range []struct {
+				name string
+				rate *rateJSON
+			}{{"core", out.Core}, {"search", out.Search}} {
+				if resource.rate != nil {
+					a.writeTSV(out.Account, resource.name, strconv.Itoa(resource.rate.Remaining),
+						strconv.Itoa(resource.rate.Limit), resource.rate.Reset)
+				}
+			}
+		}
+	}
+	if failed {
+		return errPartial
+	}
+	return nil
+}
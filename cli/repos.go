@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	githubClient "github.com/KharpukhaevV/gitui/github"
+	"github.com/KharpukhaevV/gitui/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// repoJSON репозиторий в выводе команд
+type repoJSON struct {
+	FullName      string   `json:"full_name"`
+	Owner         string   `json:"owner"`
+	Name          string   `json:"name"`
+	Host          string   `json:"host"`
+	Description   string   `json:"description,omitempty"`
+	Private       bool     `json:"private"`
+	Fork          bool     `json:"fork"`
+	Archived      bool     `json:"archived"`
+	Language      string   `json:"language,omitempty"`
+	Topics        []string `json:"topics,omitempty"`
+	License       string   `json:"license,omitempty"`
+	DefaultBranch string   `json:"default_branch,omitempty"`
+	Stars         int      `json:"stars"`
+	Forks         int      `json:"forks"`
+	OpenIssues    int      `json:"open_issues"`
+	Size          int      `json:"size_kb"`
+	UpdatedAt     string   `json:"updated_at"`
+	PushedAt      string   `json:"pushed_at,omitempty"`
+	WebURL        string   `json:"web_url"`
+	CloneURL      string   `json:"clone_url"`
+	SSHURL        string   `json:"ssh_url"`
+	Accounts      []string `json:"accounts"`
+}
+
+// newRepoJSON преобразует репозиторий для вывода
+func newRepoJSON(repo models.Repository) repoJSON {
+	out := repoJSON{
+		FullName:      repo.Title(),
+		Owner:         repo.Owner,
+		Name:          repo.Name,
+		Host:          repo.Host,
+		Description:   repo.Desc,
+		Private:       repo.IsPrivate,
+		Fork:          repo.IsFork,
+		Archived:      repo.Archived,
+		Language:      repo.Language,
+		Topics:        repo.Topics,
+		License:       repo.License,
+		DefaultBranch: repo.DefaultBranch,
+		Stars:         repo.Stars,
+		Forks:         repo.Forks,
+		OpenIssues:    repo.OpenIssues,
+		Size:          repo.Size,
+		UpdatedAt:     repo.UpdatedAt.Format(time.RFC3339),
+		WebURL:        repo.WebURL(),
+		CloneURL:      repo.CloneURL,
+		SSHURL:        repo.SSHURL,
+		Accounts:      repo.Accounts,
+	}
+	if !repo.PushedAt.IsZero() {
+		out.PushedAt = repo.PushedAt.Format(time.RFC3339)
+	}
+	return out
+}
+
+// repos выполняет команды работы с репозиториями
+func (a *app) repos(args []string) error {
+	if len(args) == 0 {
+		return usagef("repos: expected list")
+	}
+	if args[0] != "list" {
+		return usagef("repos: unknown command %q", args[0])
+	}
+	return a.reposList(args[1:])
+}
+
+// reposList выводит репозитории аккаунта или всех аккаунтов
+func (a *app) reposList(args []string) error {
+	fs := a.flagSet("repos list")
+	accountName := fs.String("account", "", "account name (default: all accounts)")
+	owner := fs.String("owner", "", "only repositories of this user or organization")
+	filter := fs.String("filter", "", "filter query, e.g. \"lang:go stars:>10 cli\"")
+	sortKey := fs.String("sort", "", "sort by name, updated, pushed, stars, forks, size or owner")
+	desc := fs.Bool("desc", false, "reverse the sort order")
+	asJSON := fs.Bool("json", false, "print JSON")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usagef("repos list: unexpected argument %q", fs.Arg(0))
+	}
+	if !validSortKey(*sortKey) {
+		return usagef("repos list: unknown sort key %q", *sortKey)
+	}
+	query, err := models.ParseQuery(*filter)
+	if err != nil {
+		return usagef("repos list: %v", err)
+	}
+
+	all, err := a.loadAccounts()
+	if err != nil {
+		return err
+	}
+	accounts, err := selectAccounts(all, *accountName)
+	if err != nil {
+		return err
+	}
+
+	repos, loadErr := a.loadRepos(accounts)
+	if loadErr != nil && !errors.Is(loadErr, errPartial) {
+		return loadErr
+	}
+	if *owner != "" {
+		repos = filterOwner(repos, *owner)
+	}
+	repos = models.SortRepos(filterRepos(repos, query), *sortKey, *desc)
+
+	if *asJSON {
+		result := make([]repoJSON, 0, len(repos))
+		for _, repo := range repos {
+			result = append(result, newRepoJSON(repo))
+		}
+		if err := a.writeJSON(result); err != nil {
+			return err
+		}
+	} else {
+		for _, repo := range repos {
+			a.writeRepoTSV(repo)
+		}
+	}
+	return loadErr
+}
+
+// loadRepos загружает репозитории аккаунтов и объединяет их в один список
+// с заполненными Accounts. Если часть аккаунтов загрузить не удалось, их ошибки
+// выводятся, а вместе с репозиториями остальных возвращается errPartial.
+func (a *app) loadRepos(accounts []models.Account) ([]models.Repository, error) {
+	job := a.client.LoadRepos(a.ctx, 0, accounts[0], models.Owner{})
+	if len(accounts) > 1 {
+		job = a.client.LoadAllRepos(a.ctx, 0, accounts)
+	}
+
+	var result models.ReposLoadedMsg
+	a.wait(job, func(msg tea.Msg) {
+		if loaded, ok := msg.(models.ReposLoadedMsg); ok && !loaded.Partial {
+			result = loaded
+		}
+	})
+	switch {
+	case a.ctx.Err() != nil:
+		return nil, a.ctx.Err()
+	case result.Err != nil && len(result.Repos) > 0:
+		fmt.Fprintf(a.stderr, "gitui: %v\n", result.Err)
+		return result.Repos, errPartial
+	case !result.RetryAt.IsZero():
+		return nil, fmt.Errorf("%v (retry after %s)", result.Err, result.RetryAt.Format(time.RFC3339))
+	case result.Err != nil:
+		return nil, result.Err
+	}
+	if len(accounts) > 1 {
+		return result.Repos, nil
+	}
+	return githubClient.MergeRepos(accounts, [][]models.Repository{result.Repos}), nil
+}
+
+// validSortKey проверяет ключ сортировки
+func validSortKey(key string) bool {
+	for _, k := range models.SortKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// filterOwner оставляет репозитории указанного владельца
+func filterOwner(repos []models.Repository, owner string) []models.Repository {
+	var result []models.Repository
+	for _, repo := range repos {
+		if strings.EqualFold(repo.Owner, owner) {
+			result = append(result, repo)
+		}
+	}
+	return result
+}
+
+// filterRepos оставляет репозитории, подходящие под запрос. В отличие от списка в TUI,
+// текст запроса ищется как подстрока без учета регистра, чтобы результат был предсказуемым.
+func filterRepos(repos []models.Repository, query models.RepoQuery) []models.Repository {
+	text := strings.ToLower(query.Text)
+	var result []models.Repository
+	for _, repo := range repos {
+		if !query.Matches(repo) {
+			continue
+		}
+		if text != "" && !strings.Contains(strings.ToLower(repo.FilterValue()), text) {
+			continue
+		}
+		result = append(result, repo)
+	}
+	return result
+}
+
+// writeRepoTSV выводит строку репозитория: имя, видимость, язык, звезды,
+// время обновления, адреса клонирования и аккаунты с доступом
+func (a *app) writeRepoTSV(repo models.Repository) {
+	visibility := "public"
+	if repo.IsPrivate {
+		visibility = "private"
+	}
+	a.writeTSV(
+		repo.Title(),
+		visibility,
+		repo.Language,
+		strconv.Itoa(repo.Stars),
+		repo.UpdatedAt.Format(time.RFC3339),
+		repo.CloneURL,
+		repo.SSHURL,
+		strings.Join(repo.Accounts, ","),
+	)
+}
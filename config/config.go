@@ -1,27 +1,57 @@
 package config
 
 import (
-	"context"
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/KharpukhaevV/gitui/models"
 	"github.com/KharpukhaevV/gitui/utils"
-	"github.com/google/go-github/github"
-	"golang.org/x/oauth2"
 )
 
+// configVersion текущая версия формата файла конфигурации
+const configVersion = 2
+
+// ErrLocked возвращается при обращении к токенам до ввода парольной фразы
+var ErrLocked = errors.New("config is locked")
+
+// configFile представляет содержимое файла конфигурации
+type configFile struct {
+	Version    int               `json:"version"`
+	Encryption *encryptionHeader `json:"encryption,omitempty"`
+	Settings   models.Settings   `json:"settings"`
+	Accounts   []models.Account  `json:"accounts"`
+}
+
 // Manager управляет конфигурацией аккаунтов
 type Manager struct {
 	configFile string
+	header     *encryptionHeader
+	key        []byte
+	needsKey   bool
+	stores     map[string]SecretStore
+	settings   models.Settings
 }
 
 // NewManager создает новый менеджер конфигурации
 func NewManager() *Manager {
-	return &Manager{
+	m := &Manager{
 		configFile: getConfigPath(),
+		stores:     defaultSecretStores(),
 	}
+	if cfg, _, err := m.read(); err == nil {
+		m.header = cfg.Encryption
+		m.settings = cfg.Settings
+		for _, acc := range cfg.Accounts {
+			if !isExternal(acc) && (acc.SealedToken != "" || acc.Token != "") {
+				m.needsKey = true
+			}
+		}
+	}
+	return m
 }
 
 // getConfigPath возвращает путь к файлу конфигурации
@@ -30,56 +60,192 @@ func getConfigPath() string {
 	return filepath.Join(home, ".github_manager.json")
 }
 
-// LoadAccounts загружает аккаунты из файла
-func (m *Manager) LoadAccounts() ([]models.Account, error) {
+// Settings возвращает общие настройки приложения
+func (m *Manager) Settings() models.Settings {
+	return m.settings
+}
+
+// NeedsUnlock сообщает, требуется ли ввод парольной фразы для загрузки аккаунтов.
+// Парольная фраза не нужна, если все токены хранятся во внешних хранилищах.
+func (m *Manager) NeedsUnlock() bool {
+	return m.key == nil && m.needsKey
+}
+
+// isExternal сообщает, хранится ли токен аккаунта вне файла конфигурации
+func isExternal(acc models.Account) bool {
+	return acc.TokenBackend != "" && acc.TokenBackend != BackendFile
+}
+
+// HasPassphrase сообщает, задана ли уже парольная фраза для файла конфигурации
+func (m *Manager) HasPassphrase() bool {
+	return m.header != nil
+}
+
+// Unlock проверяет парольную фразу или задает новую, если файл еще не зашифрован.
+// Открытые токены из старого формата файла при этом шифруются и сохраняются.
+func (m *Manager) Unlock(passphrase string) error {
+	cfg, legacy, err := m.read()
+	if err != nil {
+		return err
+	}
+
+	if cfg.Encryption != nil {
+		key, err := cfg.Encryption.unlock(passphrase)
+		if err != nil {
+			return err
+		}
+		m.header = cfg.Encryption
+		m.key = key
+		return nil
+	}
+
+	header, key, err := newEncryptionHeader(passphrase)
+	if err != nil {
+		return err
+	}
+	m.header = header
+	m.key = key
+
+	// Мигрируем открытые токены в зашифрованный формат
+	if legacy {
+		accounts, err := m.LoadAccounts()
+		if accounts != nil {
+			err = m.SaveAccounts(accounts)
+		}
+		if err != nil {
+			m.header, m.key = nil, nil
+			return err
+		}
+	}
+	return nil
+}
+
+// read читает файл конфигурации. Второе значение сообщает, что файл в старом формате
+func (m *Manager) read() (*configFile, bool, error) {
 	data, err := os.ReadFile(m.configFile)
 	if os.IsNotExist(err) {
-		return []models.Account{}, nil
+		return &configFile{Version: configVersion}, false, nil
 	}
 	if err != nil {
-		return nil, err
+		return nil, false, err
+	}
+
+	// Старый формат: массив аккаунтов с открытыми токенами
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var accounts []models.Account
+		if err := json.Unmarshal(trimmed, &accounts); err != nil {
+			return nil, false, err
+		}
+		return &configFile{Version: configVersion, Accounts: accounts}, true, nil
 	}
 
-	var accounts []models.Account
-	err = json.Unmarshal(data, &accounts)
+	var cfg configFile
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, false, err
+	}
+	return &cfg, false, nil
+}
+
+// LoadAccounts загружает аккаунты из файла и получает их токены.
+// Ошибки внешних хранилищ и адресов API не прерывают загрузку: такие аккаунты
+// возвращаются без клиента GitHub, а ошибки объединяются в возвращаемую ошибку.
+func (m *Manager) LoadAccounts() ([]models.Account, error) {
+	cfg, _, err := m.read()
 	if err != nil {
 		return nil, err
 	}
+	accounts := cfg.Accounts
+	if accounts == nil {
+		accounts = []models.Account{}
+	}
 
-	// Восстанавливаем клиенты GitHub
+	// Получаем токены и восстанавливаем клиенты GitHub
+	var accountErrs []error
 	for i := range accounts {
-		if accounts[i].Token != "" {
-			ts := oauth2.StaticTokenSource(
-				&oauth2.Token{AccessToken: accounts[i].Token},
-			)
-			tc := oauth2.NewClient(context.Background(), ts)
-			accounts[i].Client = github.NewClient(tc)
+		if isExternal(accounts[i]) {
+			token, err := m.resolveToken(accounts[i].TokenBackend, accounts[i].TokenRef)
+			if err != nil {
+				accountErrs = append(accountErrs, fmt.Errorf("%s: %v", accounts[i].Name, err))
+				continue
+			}
+			accounts[i].Token = token
+		} else if accounts[i].SealedToken != "" {
+			if m.key == nil {
+				return nil, ErrLocked
+			}
+			token, err := open(m.key, accounts[i].SealedToken)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt token for %s: %v", accounts[i].Name, err)
+			}
+			accounts[i].Token = token
+			accounts[i].SealedToken = ""
+		}
+
+		if err := accounts[i].Connect(); err != nil {
+			accountErrs = append(accountErrs, fmt.Errorf("%s: %v", accounts[i].Name, err))
 		}
 	}
 
-	return accounts, nil
+	return accounts, errors.Join(accountErrs...)
 }
 
-// SaveAccounts сохраняет аккаунты в файл
-func (m *Manager) SaveAccounts(accounts []models.Account) error {
-	if len(accounts) == 0 {
-		return nil
-	}
+// ResolveToken получает токен из внешнего хранилища по ссылке
+func (m *Manager) ResolveToken(backend, ref string) (string, error) {
+	return m.resolveToken(backend, ref)
+}
 
-	// Очищаем клиенты перед сохранением
+// SaveAccounts сохраняет аккаунты в файл, шифруя токены
+func (m *Manager) SaveAccounts(accounts []models.Account) error {
+	// Очищаем клиенты и шифруем токены перед сохранением.
+	// Токены из внешних хранилищ не сохраняются, остается только ссылка.
 	saveAccounts := make([]models.Account, len(accounts))
 	for i, acc := range accounts {
-		saveAccounts[i] = models.Account{
-			Name:    acc.Name,
-			Token:   acc.Token,
-			Created: acc.Created,
-			Private: acc.Private,
+		saveAccounts[i] = acc
+		saveAccounts[i].Token = ""
+		saveAccounts[i].SealedToken = ""
+		saveAccounts[i].Client = nil
+		if !isExternal(acc) && acc.Token != "" {
+			if m.key == nil {
+				return ErrLocked
+			}
+			sealed, err := seal(m.key, acc.Token)
+			if err != nil {
+				return err
+			}
+			saveAccounts[i].SealedToken = sealed
 		}
 	}
 
-	data, err := json.MarshalIndent(saveAccounts, "", "  ")
+	data, err := json.MarshalIndent(configFile{
+		Version:    configVersion,
+		Encryption: m.header,
+		Settings:   m.settings,
+		Accounts:   saveAccounts,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(m.configFile, data, utils.SecretFileMode)
+}
+
+// writeFileAtomic записывает файл через временный файл, чтобы не оставить его поврежденным
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(m.configFile, data, utils.DefaultFileMode)
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/KharpukhaevV/gitui/models"
+	"github.com/KharpukhaevV/gitui/utils"
+)
+
+// newTestManager создает менеджер конфигурации во временной домашней директории.
+// content записывается в файл конфигурации, если не пустой.
+func newTestManager(t *testing.T, content string) *Manager {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	if content != "" {
+		if err := os.WriteFile(getConfigPath(), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewManager()
+}
+
+// readConfig возвращает содержимое файла конфигурации
+func readConfig(t *testing.T, m *Manager) string {
+	t.Helper()
+	data, err := os.ReadFile(m.configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestSealOpen(t *testing.T) {
+	header, key, err := newEncryptionHeader("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := seal(key, "ghp_secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sealed, "ghp_secret") {
+		t.Error("sealed value contains the plaintext")
+	}
+	if plaintext, err := open(key, sealed); err != nil || plaintext != "ghp_secret" {
+		t.Errorf("open = %q, %v", plaintext, err)
+	}
+
+	// Каждое шифрование использует новый nonce
+	if again, _ := seal(key, "ghp_secret"); again == sealed {
+		t.Error("seal returned the same ciphertext twice")
+	}
+
+	unlocked, err := header.unlock("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plaintext, err := open(unlocked, sealed); err != nil || plaintext != "ghp_secret" {
+		t.Errorf("open with the unlocked key = %q, %v", plaintext, err)
+	}
+}
+
+func TestOpenTampered(t *testing.T) {
+	_, key, err := newEncryptionHeader("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := open(key, "c2hvcnQ="); err == nil {
+		t.Error("open accepted a value shorter than the nonce")
+	}
+
+	sealed, _ := seal(key, "ghp_secret")
+	data := []byte(sealed)
+	data[len(data)/2] ^= 1
+	if _, err := open(key, string(data)); err == nil {
+		t.Error("open accepted a modified ciphertext")
+	}
+}
+
+func TestUnlockWrongPassphrase(t *testing.T) {
+	m := newTestManager(t, "")
+	if err := m.Unlock("correct horse"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SaveAccounts([]models.Account{{Name: "work", Token: "ghp_secret"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	m = NewManager()
+	if !m.NeedsUnlock() || !m.HasPassphrase() {
+		t.Fatalf("NeedsUnlock = %v, HasPassphrase = %v", m.NeedsUnlock(), m.HasPassphrase())
+	}
+	if err := m.Unlock("battery staple"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("Unlock with a wrong passphrase = %v, want ErrWrongPassphrase", err)
+	}
+	if _, err := m.LoadAccounts(); !errors.Is(err, ErrLocked) {
+		t.Errorf("LoadAccounts after a failed unlock = %v, want ErrLocked", err)
+	}
+
+	if err := m.Unlock("correct horse"); err != nil {
+		t.Fatal(err)
+	}
+	accounts, err := m.LoadAccounts()
+	if err != nil || len(accounts) != 1 || accounts[0].Token != "ghp_secret" {
+		t.Errorf("LoadAccounts = %+v, %v", accounts, err)
+	}
+}
+
+func TestUnlockMigratesLegacyFile(t *testing.T) {
+	m := newTestManager(t, `[{"name":"work","token":"ghp_secret","created":"2024-01-01T00:00:00Z","private":true}]`)
+	if !m.NeedsUnlock() || m.HasPassphrase() {
+		t.Fatalf("NeedsUnlock = %v, HasPassphrase = %v", m.NeedsUnlock(), m.HasPassphrase())
+	}
+
+	if err := m.Unlock("correct horse"); err != nil {
+		t.Fatal(err)
+	}
+	content := readConfig(t, m)
+	if strings.Contains(content, "ghp_secret") {
+		t.Errorf("plaintext token left after migration:\n%s", content)
+	}
+	if !strings.Contains(content, `"sealed_token"`) || !strings.HasPrefix(content, "{") {
+		t.Errorf("config not migrated to the encrypted format:\n%s", content)
+	}
+	info, err := os.Stat(m.configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != utils.SecretFileMode {
+		t.Errorf("config mode = %o, want %o", mode, utils.SecretFileMode)
+	}
+
+	// Токен расшифровывается новым менеджером с той же парольной фразой
+	m = NewManager()
+	if err := m.Unlock("correct horse"); err != nil {
+		t.Fatal(err)
+	}
+	accounts, err := m.LoadAccounts()
+	if err != nil || len(accounts) != 1 {
+		t.Fatalf("LoadAccounts = %+v, %v", accounts, err)
+	}
+	if accounts[0].Name != "work" || accounts[0].Token != "ghp_secret" || !accounts[0].Private || accounts[0].SealedToken != "" {
+		t.Errorf("migrated account = %+v", accounts[0])
+	}
+}
+
+func TestSaveAccountsLocked(t *testing.T) {
+	m := newTestManager(t, "")
+	err := m.SaveAccounts([]models.Account{{Name: "work", Token: "ghp_secret"}})
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("SaveAccounts without a key = %v, want ErrLocked", err)
+	}
+	if _, err := os.Stat(m.configFile); !os.IsNotExist(err) {
+		t.Errorf("config written without a key: %v", err)
+	}
+
+	// Токены из внешних хранилищ не требуют ключа
+	external := models.Account{Name: "ci", Token: "ghp_secret", TokenBackend: "env", TokenRef: "GITUI_TOKEN"}
+	if err := m.SaveAccounts([]models.Account{external}); err != nil {
+		t.Fatal(err)
+	}
+	if content := readConfig(t, m); strings.Contains(content, "ghp_secret") {
+		t.Errorf("external token stored in the config:\n%s", content)
+	}
+}
+
+func TestSaveEmptyAccounts(t *testing.T) {
+	m := newTestManager(t, "")
+	if err := m.SaveAccounts(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	m = NewManager()
+	if m.NeedsUnlock() {
+		t.Error("empty config needs a passphrase")
+	}
+	accounts, err := m.LoadAccounts()
+	if err != nil || accounts == nil || len(accounts) != 0 {
+		t.Errorf("LoadAccounts = %#v, %v, want an empty list", accounts, err)
+	}
+}
+
+// deletingStore хранилище токенов, запоминающее удаленные записи
+type deletingStore struct {
+	deleted []string
+}
+
+func (s *deletingStore) Get(ref string) (string, error) {
+	return "ghp_" + ref, nil
+}
+
+func (s *deletingStore) Delete(ref string) error {
+	s.deleted = append(s.deleted, ref)
+	return nil
+}
+
+func TestDeleteToken(t *testing.T) {
+	m := newTestManager(t, "")
+	store := &deletingStore{}
+	m.RegisterSecretStore("vault", store)
+
+	work := models.Account{Name: "work", TokenBackend: "vault", TokenRef: "work"}
+	shared := models.Account{Name: "shared", TokenBackend: "vault", TokenRef: "work"}
+	env := models.Account{Name: "env", TokenBackend: BackendEnv, TokenRef: "GITHUB_TOKEN"}
+
+	// Запись, на которую ссылается другой аккаунт, остается
+	if err := m.DeleteToken(work, []models.Account{shared}); err != nil || len(store.deleted) != 0 {
+		t.Errorf("shared token: err = %v, deleted = %v", err, store.deleted)
+	}
+	if err := m.DeleteToken(work, []models.Account{env}); err != nil || strings.Join(store.deleted, ",") != "work" {
+		t.Errorf("err = %v, deleted = %v, want [work]", err, store.deleted)
+	}
+	// Хранилища без удаления не трогаются
+	if err := m.DeleteToken(env, nil); err != nil {
+		t.Errorf("env token: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Параметры scrypt по умолчанию (рекомендованные для интерактивного ввода)
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	keyLength    = 32
+	saltLength   = 16
+	checkPayload = "gitui"
+)
+
+// ErrWrongPassphrase возвращается при неверной парольной фразе
+var ErrWrongPassphrase = errors.New("wrong passphrase")
+
+// encryptionHeader описывает параметры шифрования токенов в файле конфигурации
+type encryptionHeader struct {
+	KDF   string `json:"kdf"`
+	Salt  string `json:"salt"`
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	Check string `json:"check"`
+}
+
+// newEncryptionHeader создает заголовок с новой солью и контрольным значением
+func newEncryptionHeader(passphrase string) (*encryptionHeader, []byte, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, nil, err
+	}
+
+	header := &encryptionHeader{
+		KDF:  "scrypt",
+		Salt: base64.StdEncoding.EncodeToString(salt),
+		N:    scryptN,
+		R:    scryptR,
+		P:    scryptP,
+	}
+
+	key, err := header.deriveKey(passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header.Check, err = seal(key, checkPayload)
+	if err != nil {
+		return nil, nil, err
+	}
+	return header, key, nil
+}
+
+// deriveKey получает ключ шифрования из парольной фразы
+func (h *encryptionHeader) deriveKey(passphrase string) ([]byte, error) {
+	if h.KDF != "scrypt" {
+		return nil, fmt.Errorf("unsupported kdf %q", h.KDF)
+	}
+	salt, err := base64.StdEncoding.DecodeString(h.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid salt: %v", err)
+	}
+	return scrypt.Key([]byte(passphrase), salt, h.N, h.R, h.P, keyLength)
+}
+
+// unlock проверяет парольную фразу и возвращает ключ
+func (h *encryptionHeader) unlock(passphrase string) ([]byte, error) {
+	key, err := h.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if payload, err := open(key, h.Check); err != nil || payload != checkPayload {
+		return nil, ErrWrongPassphrase
+	}
+	return key, nil
+}
+
+// seal шифрует строку с помощью AES-GCM и возвращает nonce+шифротекст в base64
+func seal(key []byte, plaintext string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// open расшифровывает строку, полученную из seal
+func open(key []byte, sealed string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("sealed value is too short")
+	}
+
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// newGCM создает AES-GCM шифр для ключа
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
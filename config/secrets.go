@@ -0,0 +1,168 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/KharpukhaevV/gitui/models"
+	"github.com/zalando/go-keyring"
+)
+
+// Названия хранилищ токенов
+const (
+	// BackendFile токен хранится зашифрованным в файле конфигурации
+	BackendFile = "file"
+	// BackendKeyring токен хранится в системном хранилище ключей
+	BackendKeyring = "keyring"
+	// BackendPass токен хранится в pass
+	BackendPass = "pass"
+	// BackendGopass токен хранится в gopass
+	BackendGopass = "gopass"
+	// BackendEnv токен берется из переменной окружения
+	BackendEnv = "env"
+	// BackendCommand токен выводится внешней командой
+	BackendCommand = "command"
+)
+
+// keyringService имя сервиса в системном хранилище ключей
+const keyringService = "gitui"
+
+// SecretStore получает токен аккаунта из внешнего хранилища по ссылке
+type SecretStore interface {
+	Get(ref string) (string, error)
+}
+
+// SecretStoreFunc позволяет использовать функцию как SecretStore
+type SecretStoreFunc func(ref string) (string, error)
+
+// Get вызывает функцию
+func (f SecretStoreFunc) Get(ref string) (string, error) {
+	return f(ref)
+}
+
+// SecretDeleter хранилище, из которого можно удалить токен удаленного аккаунта
+type SecretDeleter interface {
+	Delete(ref string) error
+}
+
+// keyringStore токены в системном хранилище ключей под сервисом gitui.
+// Записи этого сервиса принадлежат приложению, поэтому их можно удалять.
+type keyringStore struct{}
+
+// Get читает токен из системного хранилища ключей
+func (keyringStore) Get(ref string) (string, error) {
+	return keyring.Get(keyringService, ref)
+}
+
+// Delete удаляет токен из системного хранилища ключей
+func (keyringStore) Delete(ref string) error {
+	err := keyring.Delete(keyringService, ref)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// defaultSecretStores возвращает встроенные хранилища токенов
+func defaultSecretStores() map[string]SecretStore {
+	return map[string]SecretStore{
+		BackendKeyring: keyringStore{},
+		BackendPass: SecretStoreFunc(func(ref string) (string, error) {
+			return firstLine(runSecretCommand("pass", "show", ref))
+		}),
+		BackendGopass: SecretStoreFunc(func(ref string) (string, error) {
+			return firstLine(runSecretCommand("gopass", "show", "-o", ref))
+		}),
+		BackendEnv: SecretStoreFunc(func(ref string) (string, error) {
+			value, ok := os.LookupEnv(ref)
+			if !ok || value == "" {
+				return "", fmt.Errorf("environment variable %s is not set", ref)
+			}
+			return value, nil
+		}),
+		BackendCommand: SecretStoreFunc(func(ref string) (string, error) {
+			return firstLine(runSecretCommand("sh", "-c", ref))
+		}),
+	}
+}
+
+// runSecretCommand запускает команду и возвращает ее стандартный вывод
+func runSecretCommand(name string, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s failed: %v: %s", name, err, msg)
+		}
+		return "", fmt.Errorf("%s failed: %v", name, err)
+	}
+	return string(output), nil
+}
+
+// firstLine возвращает первую непустую строку вывода
+func firstLine(output string, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return "", errors.New("secret is empty")
+	}
+	return line, nil
+}
+
+// ParseTokenRef разбирает ссылку вида "backend:ref" (например, "env:GITHUB_TOKEN").
+// Возвращает false, если строка не является ссылкой на внешнее хранилище.
+func (m *Manager) ParseTokenRef(value string) (string, string, bool) {
+	backend, ref, ok := strings.Cut(value, ":")
+	if !ok || ref == "" {
+		return "", "", false
+	}
+	if _, known := m.stores[backend]; !known {
+		return "", "", false
+	}
+	return backend, ref, true
+}
+
+// RegisterSecretStore регистрирует хранилище токенов под указанным именем
+func (m *Manager) RegisterSecretStore(name string, store SecretStore) {
+	m.stores[name] = store
+}
+
+// DeleteToken удаляет токен удаленного аккаунта из внешнего хранилища, если хранилище
+// это поддерживает и на ту же запись не ссылается ни один из оставшихся accounts.
+// Записи pass, gopass, переменные окружения и команды принадлежат пользователю и не удаляются.
+func (m *Manager) DeleteToken(account models.Account, accounts []models.Account) error {
+	if !isExternal(account) {
+		return nil
+	}
+	for _, acc := range accounts {
+		if acc.TokenBackend == account.TokenBackend && acc.TokenRef == account.TokenRef {
+			return nil
+		}
+	}
+	deleter, ok := m.stores[account.TokenBackend].(SecretDeleter)
+	if !ok {
+		return nil
+	}
+	if err := deleter.Delete(account.TokenRef); err != nil {
+		return fmt.Errorf("error removing token from %s: %v", account.TokenBackend, err)
+	}
+	return nil
+}
+
+// resolveToken получает токен аккаунта из его хранилища
+func (m *Manager) resolveToken(backend, ref string) (string, error) {
+	store, ok := m.stores[backend]
+	if !ok {
+		return "", fmt.Errorf("unknown token backend %q", backend)
+	}
+	return store.Get(ref)
+}
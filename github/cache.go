@@ -0,0 +1,340 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KharpukhaevV/gitui/models"
+	"github.com/KharpukhaevV/gitui/utils"
+	"github.com/google/go-github/github"
+)
+
+// cacheVersion версия формата файла кэша репозиториев
+const cacheVersion = 2
+
+// fetchWorkers максимальное количество страниц, загружаемых одновременно
+const fetchWorkers = 4
+
+// cacheDirMode права доступа к директории кэша: в кэше есть имена приватных репозиториев
+const cacheDirMode = 0700
+
+// mediaTypeTopics тип ответа API, включающий темы репозиториев
+const mediaTypeTopics = "application/vnd.github.mercy-preview+json"
+
+// unsafeNamePattern находит символы, недопустимые в имени файла кэша
+var unsafeNamePattern = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// repoCache список репозиториев аккаунта, сохраненный на диске
+type repoCache struct {
+	Version   int          `json:"version"`
+	FetchedAt time.Time    `json:"fetched_at"`
+	Pages     []cachedPage `json:"pages"`
+}
+
+// cachedPage страница ответа API с заголовками для условного запроса
+type cachedPage struct {
+	ETag         string              `json:"etag,omitempty"`
+	LastModified string              `json:"last_modified,omitempty"`
+	NextPage     int                 `json:"next_page"`
+	Repos        []models.Repository `json:"repos"`
+}
+
+// repos возвращает репозитории всех страниц кэша
+func (rc *repoCache) repos() []models.Repository {
+	var repos []models.Repository
+	for _, page := range rc.Pages {
+		repos = append(repos, page.Repos...)
+	}
+	return repos
+}
+
+// defaultCacheDir возвращает директорию кэша приложения или пустую строку,
+// если ее не удалось определить (тогда кэш не используется)
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "gitui")
+}
+
+// cachePath возвращает путь к файлу кэша репозиториев аккаунта.
+// Для организаций кэшируется их список, для остальных владельцев — общий список пользователя.
+func (c *Client) cachePath(account models.Account, owner models.Owner) string {
+	if c.CacheDir == "" {
+		return ""
+	}
+	scope := "user"
+	if owner.Org {
+		scope = "org-" + owner.Login
+	}
+	return filepath.Join(c.accountCacheDir(account.Name), unsafeNamePattern.ReplaceAllString(scope, "_")+".json")
+}
+
+// accountCacheDir возвращает директорию с кэшем всех владельцев аккаунта
+func (c *Client) accountCacheDir(name string) string {
+	return filepath.Join(c.CacheDir, "repos", unsafeNamePattern.ReplaceAllString(name, "_"))
+}
+
+// ForgetAccount удаляет кэш репозиториев и известные лимиты запросов удаленного аккаунта
+func (c *Client) ForgetAccount(account models.Account) {
+	c.ratesMu.Lock()
+	delete(c.rates, account.Name)
+	c.ratesMu.Unlock()
+	if c.CacheDir != "" {
+		os.RemoveAll(c.accountCacheDir(account.Name))
+	}
+}
+
+// RenameAccount переносит кэш репозиториев и лимиты запросов аккаунта на новое имя.
+// Если перенести кэш не удалось, он удаляется и список загрузится из API заново.
+func (c *Client) RenameAccount(account models.Account, name string) {
+	c.ratesMu.Lock()
+	if rate, ok := c.rates[account.Name]; ok {
+		delete(c.rates, account.Name)
+		c.rates[name] = rate
+	}
+	c.ratesMu.Unlock()
+
+	if c.CacheDir == "" {
+		return
+	}
+	from, to := c.accountCacheDir(account.Name), c.accountCacheDir(name)
+	if from == to {
+		return
+	}
+	os.RemoveAll(to)
+	if err := os.Rename(from, to); err != nil {
+		os.RemoveAll(from)
+	}
+}
+
+// readCache читает кэш репозиториев. Отсутствующий или поврежденный кэш означает промах.
+func (c *Client) readCache(account models.Account, owner models.Owner) *repoCache {
+	path := c.cachePath(account, owner)
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var cache repoCache
+	if err := json.Unmarshal(data, &cache); err != nil || cache.Version != cacheVersion {
+		return nil
+	}
+	return &cache
+}
+
+// writeCache сохраняет кэш репозиториев. Кэш необязателен, поэтому ошибки записи игнорируются.
+func (c *Client) writeCache(account models.Account, owner models.Owner, cache *repoCache) {
+	path := c.cachePath(account, owner)
+	if path == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), cacheDirMode); err != nil {
+		return
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, utils.SecretFileMode); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+	}
+}
+
+// CachedRepos возвращает сохраненный на диске список репозиториев владельца
+// и время его последней загрузки из API
+func (c *Client) CachedRepos(account models.Account, owner models.Owner) ([]models.Repository, time.Time, bool) {
+	cache := c.readCache(account, owner)
+	if cache == nil {
+		return nil, time.Time{}, false
+	}
+	return filterByOwner(cache.repos(), owner), cache.FetchedAt, true
+}
+
+// filterByOwner оставляет репозитории владельца, если это не организация
+func filterByOwner(repos []models.Repository, owner models.Owner) []models.Repository {
+	if owner.Login == "" || owner.Org {
+		return repos
+	}
+	var filtered []models.Repository
+	for _, repo := range repos {
+		if strings.EqualFold(repo.Owner, owner.Login) {
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered
+}
+
+// pageResult результат загрузки одной страницы списка репозиториев
+type pageResult struct {
+	page        cachedPage
+	notModified bool
+	lastPage    int
+}
+
+// fetchRepos загружает все страницы списка репозиториев. Первая страница загружается
+// отдельно, и по ее заголовку Link остальные страницы загружаются параллельно
+// (не больше fetchWorkers одновременно). Для каждой новой страницы вызывается onPage,
+// возможно из разных горутин.
+//
+// Для страниц из кэша отправляются условные запросы (If-None-Match / If-Modified-Since):
+// ответ 304 не расходует лимит запросов, и страница берется из кэша.
+// Второе значение сообщает, что ни одна страница не изменилась.
+func (c *Client) fetchRepos(ctx context.Context, account models.Account, owner models.Owner, cache *repoCache,
+	onPage func([]models.Repository)) (*repoCache, bool, error) {
+	endpoint := "user/repos?type=all&per_page=100"
+	if owner.Org {
+		endpoint = fmt.Sprintf("orgs/%s/repos?type=all&per_page=100", url.PathEscape(owner.Login))
+	}
+	cachedAt := func(page int) *cachedPage {
+		if cache != nil && page <= len(cache.Pages) {
+			return &cache.Pages[page-1]
+		}
+		return nil
+	}
+
+	first, err := c.fetchPage(ctx, account, endpoint, 1, cachedAt(1))
+	if err != nil {
+		return nil, false, err
+	}
+	results := []pageResult{first}
+	if !first.notModified {
+		onPage(first.page.Repos)
+	}
+
+	// Количество страниц известно из заголовка Link, а при ответе 304 — из кэша
+	lastPage := first.lastPage
+	if lastPage == 0 && first.notModified && first.page.NextPage != 0 {
+		lastPage = len(cache.Pages)
+	}
+
+	if lastPage > 1 {
+		rest := make([]pageResult, lastPage-1)
+		pageCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var (
+			wg       sync.WaitGroup
+			mu       sync.Mutex
+			firstErr error
+		)
+		pages := make(chan int)
+		for i := 0; i < utils.Min(fetchWorkers, lastPage-1); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for page := range pages {
+					result, err := c.fetchPage(pageCtx, account, endpoint, page, cachedAt(page))
+					if err != nil {
+						mu.Lock()
+						if firstErr == nil {
+							firstErr = err
+							cancel()
+						}
+						mu.Unlock()
+						continue
+					}
+					rest[page-2] = result
+					if !result.notModified {
+						onPage(result.page.Repos)
+					}
+				}
+			}()
+		}
+		for page := 2; page <= lastPage; page++ {
+			select {
+			case pages <- page:
+			case <-pageCtx.Done():
+			}
+		}
+		close(pages)
+		wg.Wait()
+
+		if firstErr != nil {
+			return nil, false, firstErr
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+		results = append(results, rest...)
+	}
+
+	// Если после последней известной страницы появились новые, дочитываем их по порядку
+	for next := results[len(results)-1].page.NextPage; next != 0; {
+		result, err := c.fetchPage(ctx, account, endpoint, next, cachedAt(next))
+		if err != nil {
+			return nil, false, err
+		}
+		if !result.notModified {
+			onPage(result.page.Repos)
+		}
+		results = append(results, result)
+		next = result.page.NextPage
+	}
+
+	fresh := &repoCache{Version: cacheVersion, FetchedAt: time.Now()}
+	notModified := cache != nil && len(cache.Pages) == len(results)
+	for _, result := range results {
+		fresh.Pages = append(fresh.Pages, result.page)
+		notModified = notModified && result.notModified
+	}
+	return fresh, notModified, nil
+}
+
+// fetchPage загружает страницу списка репозиториев, используя условный запрос для страницы из кэша
+func (c *Client) fetchPage(ctx context.Context, account models.Account, endpoint string, page int, cached *cachedPage) (pageResult, error) {
+	req, err := account.Client.NewRequest("GET", fmt.Sprintf("%s&page=%d", endpoint, page), nil)
+	if err != nil {
+		return pageResult{}, err
+	}
+	// Темы репозиториев GitHub Enterprise отдает только с этим типом ответа
+	req.Header.Set("Accept", mediaTypeTopics)
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	var repos []*github.Repository
+	resp, err := account.Client.Do(ctx, req, &repos)
+	c.recordRate(account, resp)
+	if cached != nil && resp != nil && resp.StatusCode == http.StatusNotModified {
+		result := pageResult{page: *cached, notModified: true, lastPage: resp.LastPage}
+		if resp.NextPage != 0 {
+			result.page.NextPage = resp.NextPage
+		}
+		return result, nil
+	}
+	if err != nil {
+		return pageResult{}, err
+	}
+
+	fetched := cachedPage{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		NextPage:     resp.NextPage,
+	}
+	for _, repo := range repos {
+		fetched.Repos = append(fetched.Repos, convertRepo(repo, account.WebHost()))
+	}
+	return pageResult{page: fetched, lastPage: resp.LastPage}, nil
+}
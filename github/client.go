@@ -3,159 +3,234 @@ package github
 import (
 	"context"
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
+	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/KharpukhaevV/gitui/models"
-	"github.com/KharpukhaevV/gitui/utils"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/google/go-github/github"
 )
 
 // Client предоставляет методы для работы с GitHub API
-type Client struct{}
+type Client struct {
+	Settings models.Settings
+	CacheDir string
+	ratesMu  sync.Mutex
+	rates    map[string]models.RateLimit
+	readmeMu sync.Mutex
+	readmes  map[string]string
+}
 
 // NewClient создает новый клиент GitHub
-func NewClient() *Client {
-	return &Client{}
+func NewClient(settings models.Settings) *Client {
+	return &Client{
+		Settings: settings,
+		CacheDir: defaultCacheDir(),
+		rates:    map[string]models.RateLimit{},
+		readmes:  map[string]string{},
+	}
 }
 
-// LoadRepos загружает репозитории для указанного аккаунта
-func (c *Client) LoadRepos(account *models.Account) tea.Cmd {
+// ValidateAccount проверяет токен аккаунта и получает данные пользователя GitHub.
+// request возвращается в сообщении, чтобы UI мог отбросить устаревший ответ.
+func (c *Client) ValidateAccount(ctx context.Context, request int, account models.Account) tea.Cmd {
 	return func() tea.Msg {
-		if account == nil {
-			return models.ReposLoadedMsg{Err: fmt.Errorf("account is nil")}
-		}
 		if account.Client == nil {
-			return models.ReposLoadedMsg{Err: fmt.Errorf("GitHub client not initialized")}
+			return models.AccountValidatedMsg{Request: request, Account: account, Err: fmt.Errorf("GitHub client not initialized")}
 		}
 
-		var allRepos []*github.Repository
-		opt := &github.RepositoryListOptions{
-			Type:        "all",
-			ListOptions: github.ListOptions{PerPage: 100},
+		user, resp, err := account.Client.Users.Get(ctx, "")
+		c.recordRate(account, resp)
+		if err != nil {
+			if errResp, ok := err.(*github.ErrorResponse); ok && errResp.Response.StatusCode == http.StatusUnauthorized {
+				err = fmt.Errorf("token rejected by GitHub: %s", errResp.Message)
+			}
+			return models.AccountValidatedMsg{Request: request, Account: account, Err: err}
 		}
 
-		for {
-			repos, resp, err := account.Client.Repositories.List(context.Background(), "", opt)
-			if err != nil {
-				return models.ReposLoadedMsg{Err: err}
-			}
-			allRepos = append(allRepos, repos...)
-			if resp.NextPage == 0 {
-				break
-			}
-			opt.Page = resp.NextPage
+		account.Login = user.GetLogin()
+		account.AvatarURL = user.GetAvatarURL()
+
+		// Заголовок X-OAuth-Scopes есть только у классических токенов
+		if header, ok := resp.Header["X-Oauth-Scopes"]; ok {
+			account.Scopes = models.ParseScopes(strings.Join(header, ","))
+			account.Private = account.HasScope("repo")
+		} else {
+			account.Scopes = nil
+			account.Private = true
 		}
 
-		var convertedRepos []models.Repository
-		for _, repo := range allRepos {
-			language := ""
-			if repo.Language != nil {
-				language = *repo.Language
-			}
+		return models.AccountValidatedMsg{Request: request, Account: account}
+	}
+}
 
-			description := ""
-			if repo.Description != nil {
-				description = *repo.Description
-			}
+// LoadRepos загружает репозитории для указанного аккаунта и обновляет их кэш на диске.
+// Пустой owner означает все репозитории, с которыми связан пользователь. Для организации
+// загружается ее полный список репозиториев, включая доступные через команды.
+// Для остальных владельцев список пользователя фильтруется по владельцу.
+//
+// Задача передает ReposLoadedMsg с Partial для каждой новой загруженной страницы
+// и итоговый ReposLoadedMsg с полным списком. Загрузка прерывается при отмене ctx.
+func (c *Client) LoadRepos(ctx context.Context, request int, account models.Account, owner models.Owner) *Job {
+	job := newJobContext(ctx)
+	go func() {
+		defer job.close()
+
+		msg := models.ReposLoadedMsg{Request: request, Account: account.Name, Owner: owner}
+		if account.Client == nil {
+			msg.Err = fmt.Errorf("GitHub client not initialized")
+			job.deliver(msg)
+			return
+		}
 
-			owner := ""
-			if repo.Owner != nil && repo.Owner.Login != nil {
-				owner = *repo.Owner.Login
+		onPage := func(repos []models.Repository) {
+			job.deliver(models.ReposLoadedMsg{
+				Request: request,
+				Account: account.Name,
+				Owner:   owner,
+				Repos:   filterByOwner(repos, owner),
+				Partial: true,
+			})
+		}
+		cache, notModified, err := c.fetchRepos(job.ctx, account, owner, c.readCache(account, owner), onPage)
+		if err != nil {
+			// При превышении лимита UI повторит загрузку после его сброса
+			if retryAt, ok := c.rateLimitRetry(account, err); ok {
+				msg.RetryAt = retryAt
 			}
+			msg.Err = err
+			job.deliver(msg)
+			return
+		}
+		c.writeCache(account, owner, cache)
+
+		msg.Repos = filterByOwner(cache.repos(), owner)
+		msg.FetchedAt = cache.FetchedAt
+		msg.NotModified = notModified
+		job.deliver(msg)
+	}()
+	return job
+}
 
-			sshURL := ""
-			if repo.SSHURL != nil {
-				sshURL = *repo.SSHURL
-			}
+// LoadOwners загружает владельцев, репозитории которых можно просматривать:
+// самого пользователя и организации, в которых он состоит
+func (c *Client) LoadOwners(ctx context.Context, request int, account models.Account) tea.Cmd {
+	return func() tea.Msg {
+		if account.Client == nil {
+			return models.OwnersLoadedMsg{Request: request, Err: fmt.Errorf("GitHub client not initialized")}
+		}
 
-			cloneURL := ""
-			if repo.CloneURL != nil {
-				cloneURL = *repo.CloneURL
+		login := account.Login
+		if login == "" {
+			user, resp, err := account.Client.Users.Get(ctx, "")
+			c.recordRate(account, resp)
+			if err != nil {
+				return models.OwnersLoadedMsg{Request: request, Err: err}
 			}
+			login = user.GetLogin()
+		}
+		owners := []models.Owner{{Login: login}}
 
-			updatedAt := time.Now()
-			if repo.UpdatedAt != nil {
-				updatedAt = repo.UpdatedAt.Time
+		opt := &github.ListOptions{PerPage: 100}
+		for {
+			orgs, resp, err := account.Client.Organizations.List(ctx, "", opt)
+			c.recordRate(account, resp)
+			if err != nil {
+				return models.OwnersLoadedMsg{Request: request, Err: err}
 			}
-
-			convertedRepos = append(convertedRepos, models.Repository{
-				Name:      repo.GetName(),
-				Desc:      description,
-				Stars:     repo.GetStargazersCount(),
-				Forks:     repo.GetForksCount(),
-				Language:  language,
-				UpdatedAt: updatedAt,
-				IsPrivate: repo.GetPrivate(),
-				SSHURL:    sshURL,
-				CloneURL:  cloneURL,
-				Owner:     owner,
-			})
+			for _, org := range orgs {
+				owners = append(owners, models.Owner{Login: org.GetLogin(), Org: true})
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opt.Page = resp.NextPage
 		}
 
-		return models.ReposLoadedMsg{Repos: convertedRepos}
+		return models.OwnersLoadedMsg{Request: request, Owners: owners}
 	}
 }
 
-// CloneRepo клонирует репозиторий
-func (c *Client) CloneRepo(repo models.Repository, token string) tea.Cmd {
-	return func() tea.Msg {
-		if token == "" {
-			return models.CloneMsg{Repo: repo, Success: false, Err: fmt.Errorf("token is empty")}
-		}
-		if repo.Owner == "" || repo.Name == "" {
-			return models.CloneMsg{Repo: repo, Success: false, Err: fmt.Errorf("repository owner or name is empty")}
-		}
+// GetRepo загружает репозиторий по владельцу и имени
+func (c *Client) GetRepo(ctx context.Context, account models.Account, owner, name string) (models.Repository, error) {
+	if account.Client == nil {
+		return models.Repository{}, fmt.Errorf("GitHub client not initialized")
+	}
+	repo, resp, err := account.Client.Repositories.Get(ctx, owner, name)
+	c.recordRate(account, resp)
+	if err != nil {
+		return models.Repository{}, err
+	}
+	return convertRepo(repo, account.WebHost()), nil
+}
 
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return models.CloneMsg{Repo: repo, Success: false, Err: fmt.Errorf("failed to get home directory: %v", err)}
-		}
+// convertRepo преобразует репозиторий GitHub API в модель приложения
+func convertRepo(repo *github.Repository, host string) models.Repository {
+	language := ""
+	if repo.Language != nil {
+		language = *repo.Language
+	}
 
-		// Создаем путь: ~/develop/owner/repo-name
-		devDir := filepath.Join(home, "develop")
-		if err := os.MkdirAll(devDir, utils.DefaultDirMode); err != nil {
-			return models.CloneMsg{Repo: repo, Success: false, Err: fmt.Errorf("failed to create develop directory: %v", err)}
-		}
+	description := ""
+	if repo.Description != nil {
+		description = *repo.Description
+	}
 
-		repoDir := filepath.Join(devDir, repo.Name)
+	owner := ""
+	if repo.Owner != nil && repo.Owner.Login != nil {
+		owner = *repo.Owner.Login
+	}
 
-		// Проверяем, существует ли репозиторий
-		if _, err := os.Stat(repoDir); err == nil {
-			return models.CloneMsg{
-				Repo:    repo,
-				Success: false,
-				Err:     fmt.Errorf("repository already exists at %s", repoDir),
-				Path:    repoDir,
-			}
-		}
+	sshURL := ""
+	if repo.SSHURL != nil {
+		sshURL = *repo.SSHURL
+	}
 
-		// Создаем URL с токеном для аутентификации
-		cloneURL := fmt.Sprintf("https://%s:%s@github.com/%s/%s.git",
-			"oauth2",
-			token,
-			repo.Owner,
-			repo.Name)
+	cloneURL := ""
+	if repo.CloneURL != nil {
+		cloneURL = *repo.CloneURL
+	}
 
-		// Клонируем репозиторий
-		cmd := exec.Command("git", "clone", cloneURL, repoDir)
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			return models.CloneMsg{
-				Repo:    repo,
-				Success: false,
-				Err:     fmt.Errorf("git clone failed: %v, output: %s", err, string(output)),
-				Path:    repoDir,
-			}
-		}
+	updatedAt := time.Now()
+	if repo.UpdatedAt != nil {
+		updatedAt = repo.UpdatedAt.Time
+	}
 
-		return models.CloneMsg{
-			Repo:    repo,
-			Success: true,
-			Path:    repoDir,
+	var pushedAt time.Time
+	if repo.PushedAt != nil {
+		pushedAt = repo.PushedAt.Time
+	}
+
+	license := ""
+	if repo.License != nil {
+		license = repo.License.GetSPDXID()
+		if license == "" || license == "NOASSERTION" {
+			license = repo.License.GetName()
 		}
 	}
+
+	return models.Repository{
+		Name:          repo.GetName(),
+		Desc:          description,
+		Stars:         repo.GetStargazersCount(),
+		Forks:         repo.GetForksCount(),
+		Language:      language,
+		UpdatedAt:     updatedAt,
+		PushedAt:      pushedAt,
+		IsPrivate:     repo.GetPrivate(),
+		IsFork:        repo.GetFork(),
+		Archived:      repo.GetArchived(),
+		SSHURL:        sshURL,
+		CloneURL:      cloneURL,
+		Owner:         owner,
+		Host:          host,
+		Topics:        repo.Topics,
+		DefaultBranch: repo.GetDefaultBranch(),
+		License:       license,
+		Size:          repo.GetSize(),
+		OpenIssues:    repo.GetOpenIssuesCount(),
+		Homepage:      repo.GetHomepage(),
+	}
 }
@@ -0,0 +1,297 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KharpukhaevV/gitui/github/githubtest"
+	"github.com/KharpukhaevV/gitui/models"
+	"github.com/google/go-github/github"
+)
+
+// newTestClient создает клиент с кэшем и директорией клонирования во временных директориях
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	c := NewClient(models.Settings{CloneRoot: t.TempDir(), CloneTemplate: "{root}/{owner}/{name}"})
+	c.CacheDir = t.TempDir()
+	return c
+}
+
+// drainRepos читает сообщения задачи загрузки и возвращает страницы и итоговое сообщение
+func drainRepos(t *testing.T, job *Job) ([]models.ReposLoadedMsg, models.ReposLoadedMsg) {
+	t.Helper()
+	var partials []models.ReposLoadedMsg
+	var final *models.ReposLoadedMsg
+	for {
+		msg := job.Wait()()
+		if msg == nil {
+			break
+		}
+		loaded, ok := msg.(models.ReposLoadedMsg)
+		if !ok {
+			t.Fatalf("unexpected message %T", msg)
+		}
+		if final != nil {
+			t.Fatalf("message after the final one: %+v", loaded)
+		}
+		if loaded.Partial {
+			partials = append(partials, loaded)
+			continue
+		}
+		final = &loaded
+	}
+	if final == nil {
+		t.Fatal("job closed without the final message")
+	}
+	return partials, *final
+}
+
+// repoNames возвращает полные имена репозиториев
+func repoNames(repos []models.Repository) []string {
+	names := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		names = append(names, repo.Title())
+	}
+	return names
+}
+
+func TestLoadReposPagination(t *testing.T) {
+	fake := githubtest.NewServer(t,
+		[]map[string]interface{}{githubtest.Repo("octocat", "a"), githubtest.Repo("octocat", "b")},
+		[]map[string]interface{}{githubtest.Repo("acme", "c")},
+		[]map[string]interface{}{githubtest.Repo("acme", "d"), githubtest.Repo("octocat", "e")},
+	)
+	c := newTestClient(t)
+	account := fake.Account(t)
+
+	partials, final := drainRepos(t, c.LoadRepos(context.Background(), 7, account, models.Owner{}))
+	if final.Err != nil {
+		t.Fatalf("LoadRepos: %v", final.Err)
+	}
+	want := "octocat/a octocat/b acme/c acme/d octocat/e"
+	if got := strings.Join(repoNames(final.Repos), " "); got != want {
+		t.Errorf("repos = %s, want %s", got, want)
+	}
+	if len(partials) != 3 {
+		t.Errorf("got %d page messages, want 3", len(partials))
+	}
+	if final.Request != 7 || final.Account != "work" || final.NotModified || final.FetchedAt.IsZero() {
+		t.Errorf("unexpected final message: %+v", final)
+	}
+
+	repo := final.Repos[0]
+	if repo.Desc != "repo a" || repo.Language != "Go" || !repo.IsPrivate || repo.License != "MIT" ||
+		len(repo.Topics) != 1 || repo.Host != account.WebHost() {
+		t.Errorf("unexpected converted repository: %+v", repo)
+	}
+
+	// Повторная загрузка отправляет условные запросы и берет страницы из кэша
+	partials, final = drainRepos(t, c.LoadRepos(context.Background(), 8, account, models.Owner{}))
+	if final.Err != nil || !final.NotModified || len(partials) != 0 {
+		t.Errorf("reload: err=%v notModified=%v pages=%d, want cached result", final.Err, final.NotModified, len(partials))
+	}
+	if got := strings.Join(repoNames(final.Repos), " "); got != want {
+		t.Errorf("reloaded repos = %s, want %s", got, want)
+	}
+	for page := 1; page <= 3; page++ {
+		if n := fake.PageRequests(page); n != 2 {
+			t.Errorf("page %d requested %d times, want 2", page, n)
+		}
+	}
+
+	cached, _, ok := c.CachedRepos(account, models.Owner{})
+	if !ok || len(cached) != 5 {
+		t.Errorf("CachedRepos = %d repos, ok=%v, want 5", len(cached), ok)
+	}
+}
+
+func TestLoadReposOwnerFilter(t *testing.T) {
+	fake := githubtest.NewServer(t, []map[string]interface{}{githubtest.Repo("octocat", "a"), githubtest.Repo("acme", "b")})
+	c := newTestClient(t)
+
+	_, final := drainRepos(t, c.LoadRepos(context.Background(), 1, fake.Account(t), models.Owner{Login: "ACME"}))
+	if final.Err != nil {
+		t.Fatalf("LoadRepos: %v", final.Err)
+	}
+	if got := repoNames(final.Repos); len(got) != 1 || got[0] != "acme/b" {
+		t.Errorf("repos = %v, want [acme/b]", got)
+	}
+}
+
+func TestLoadReposErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		header    map[string]string
+		wantRetry bool
+	}{
+		{name: "server error", status: http.StatusInternalServerError},
+		{name: "unauthorized", status: http.StatusUnauthorized},
+		{
+			name:      "rate limit",
+			status:    http.StatusForbidden,
+			header:    map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)},
+			wantRetry: true,
+		},
+		{name: "secondary rate limit", status: http.StatusTooManyRequests, header: map[string]string{"Retry-After": "30"}, wantRetry: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := githubtest.NewServer(t, []map[string]interface{}{githubtest.Repo("octocat", "a")})
+			fake.Fail(tt.status, tt.header)
+			c := newTestClient(t)
+			account := fake.Account(t)
+
+			partials, final := drainRepos(t, c.LoadRepos(context.Background(), 1, account, models.Owner{}))
+			if final.Err == nil {
+				t.Fatal("expected an error")
+			}
+			if len(partials) != 0 || len(final.Repos) != 0 {
+				t.Errorf("got repositories on error: %d pages, %d repos", len(partials), len(final.Repos))
+			}
+			if got := !final.RetryAt.IsZero(); got != tt.wantRetry {
+				t.Errorf("RetryAt = %v, want retry %v", final.RetryAt, tt.wantRetry)
+			}
+			if _, _, ok := c.CachedRepos(account, models.Owner{}); ok {
+				t.Error("cache written after a failed load")
+			}
+		})
+	}
+}
+
+func TestLoadReposFailedPageKeepsCache(t *testing.T) {
+	fake := githubtest.NewServer(t,
+		[]map[string]interface{}{githubtest.Repo("octocat", "a")},
+		[]map[string]interface{}{githubtest.Repo("octocat", "b")},
+	)
+	c := newTestClient(t)
+	account := fake.Account(t)
+	if _, final := drainRepos(t, c.LoadRepos(context.Background(), 1, account, models.Owner{})); final.Err != nil {
+		t.Fatalf("LoadRepos: %v", final.Err)
+	}
+
+	fake.Fail(http.StatusBadGateway, nil)
+	if _, final := drainRepos(t, c.LoadRepos(context.Background(), 2, account, models.Owner{})); final.Err == nil {
+		t.Fatal("expected an error")
+	}
+	if cached, _, ok := c.CachedRepos(account, models.Owner{}); !ok || len(cached) != 2 {
+		t.Errorf("cache after failed refresh = %d repos, ok=%v, want the previous 2", len(cached), ok)
+	}
+}
+
+func TestLoadReposNilFields(t *testing.T) {
+	// В ответе API могут отсутствовать владелец, описание, язык, лицензия и даты
+	fake := githubtest.NewServer(t, []map[string]interface{}{{"name": "bare"}})
+	c := newTestClient(t)
+	account := fake.Account(t)
+
+	_, final := drainRepos(t, c.LoadRepos(context.Background(), 1, account, models.Owner{}))
+	if final.Err != nil {
+		t.Fatalf("LoadRepos: %v", final.Err)
+	}
+	if len(final.Repos) != 1 {
+		t.Fatalf("got %d repos, want 1", len(final.Repos))
+	}
+	repo := final.Repos[0]
+	if repo.Name != "bare" || repo.Owner != "" || repo.Desc != "" || repo.Language != "" ||
+		repo.License != "" || repo.CloneURL != "" || repo.SSHURL != "" || repo.Topics != nil {
+		t.Errorf("unexpected repository: %+v", repo)
+	}
+	if repo.UpdatedAt.IsZero() || !repo.PushedAt.IsZero() {
+		t.Errorf("UpdatedAt = %v, PushedAt = %v", repo.UpdatedAt, repo.PushedAt)
+	}
+	if repo.Host != account.WebHost() {
+		t.Errorf("Host = %q, want %q", repo.Host, account.WebHost())
+	}
+}
+
+func TestLoadReposWithoutClient(t *testing.T) {
+	c := newTestClient(t)
+	_, final := drainRepos(t, c.LoadRepos(context.Background(), 1, models.Account{Name: "empty"}, models.Owner{}))
+	if final.Err == nil {
+		t.Fatal("expected an error for an account without a client")
+	}
+}
+
+func TestLoadReposCanceled(t *testing.T) {
+	fake := githubtest.NewServer(t, []map[string]interface{}{githubtest.Repo("octocat", "a")})
+	c := newTestClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Отмененная загрузка закрывает задачу, не дожидаясь чтения сообщений
+	job := c.LoadRepos(ctx, 1, fake.Account(t), models.Owner{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for job.Wait()() != nil {
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("canceled job was not closed")
+	}
+}
+
+func TestValidateAccount(t *testing.T) {
+	fake := githubtest.NewServer(t)
+	c := newTestClient(t)
+
+	msg := c.ValidateAccount(context.Background(), 3, fake.Account(t))().(models.AccountValidatedMsg)
+	if msg.Err != nil {
+		t.Fatalf("ValidateAccount: %v", msg.Err)
+	}
+	if msg.Request != 3 || msg.Account.Login != "octocat" || !msg.Account.HasScope("read:org") || !msg.Account.Private {
+		t.Errorf("unexpected account: %+v", msg.Account)
+	}
+
+	msg = c.ValidateAccount(context.Background(), 4, models.Account{Name: "empty"})().(models.AccountValidatedMsg)
+	if msg.Err == nil {
+		t.Error("expected an error for an account without a client")
+	}
+}
+
+func TestForgetAndRenameAccount(t *testing.T) {
+	fake := githubtest.NewServer(t, []map[string]interface{}{githubtest.Repo("octocat", "a")})
+	c := newTestClient(t)
+	account := fake.Account(t)
+	if _, final := drainRepos(t, c.LoadRepos(context.Background(), 1, account, models.Owner{})); final.Err != nil {
+		t.Fatalf("LoadRepos: %v", final.Err)
+	}
+	c.setRate(account, github.Rate{Limit: 5000, Remaining: 4999})
+
+	// После переименования кэш и лимиты доступны под новым именем
+	c.RenameAccount(account, "job")
+	renamed := account
+	renamed.Name = "job"
+	if _, _, ok := c.CachedRepos(account, models.Owner{}); ok {
+		t.Error("cache left under the old name")
+	}
+	if repos, _, ok := c.CachedRepos(renamed, models.Owner{}); !ok || len(repos) != 1 {
+		t.Errorf("cache under the new name = %v, %v", repos, ok)
+	}
+	if _, ok := c.RateLimit(account); ok {
+		t.Error("rate limit left under the old name")
+	}
+	if rate, ok := c.RateLimit(renamed); !ok || rate.Remaining != 4999 {
+		t.Errorf("rate limit under the new name = %+v, %v", rate, ok)
+	}
+
+	c.ForgetAccount(renamed)
+	if _, _, ok := c.CachedRepos(renamed, models.Owner{}); ok {
+		t.Error("cache left after ForgetAccount")
+	}
+	if _, ok := c.RateLimit(renamed); ok {
+		t.Error("rate limit left after ForgetAccount")
+	}
+	if entries, _ := os.ReadDir(filepath.Join(c.CacheDir, "repos")); len(entries) != 0 {
+		t.Errorf("cache entries left: %v", entries)
+	}
+}
@@ -0,0 +1,294 @@
+package github
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KharpukhaevV/gitui/models"
+	"github.com/KharpukhaevV/gitui/utils"
+)
+
+// progressPattern находит фазу и процент в выводе git --progress
+// (например, "Receiving objects:  45% (450/1000)")
+var progressPattern = regexp.MustCompile(`^(?:remote: )?([A-Za-z ]+):\s+(\d+)%`)
+
+// killDelay время ожидания завершения git после отмены
+const killDelay = 2 * time.Second
+
+// CloneTask репозиторий в очереди клонирования.
+// Action задает действие: клонирование (по умолчанию), fetch или pull уже склонированного репозитория.
+type CloneTask struct {
+	Repo     models.Repository
+	Account  models.Account
+	Protocol string
+	Action   string
+}
+
+// CloneRepo запускает клонирование репозитория по HTTPS с токеном или по SSH.
+// Пустой protocol означает протокол аккаунта по умолчанию.
+func (c *Client) CloneRepo(repo models.Repository, account models.Account, protocol string) *Job {
+	return c.CloneQueue([]CloneTask{{Repo: repo, Account: account, Protocol: protocol}})
+}
+
+// CloneQueue клонирует репозитории пулом из Settings.Workers() параллельных воркеров.
+// Задачи с одинаковым путем клона выполняются одна за другой одним воркером.
+// Задача передает CloneProgressMsg по мере выполнения, CloneMsg для каждого
+// репозитория и CloneQueueDoneMsg после завершения всей очереди.
+func (c *Client) CloneQueue(tasks []CloneTask) *Job {
+	job := newJob()
+	go func() {
+		groups := c.groupByPath(tasks)
+		queue := make(chan []CloneTask)
+		results := make(chan models.CloneMsg)
+
+		var wg sync.WaitGroup
+		for i := 0; i < utils.Min(c.Settings.Workers(), len(groups)); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for group := range queue {
+					for _, task := range group {
+						results <- c.run(job, task)
+					}
+				}
+			}()
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(queue)
+			for _, group := range groups {
+				select {
+				case queue <- group:
+				case <-job.ctx.Done():
+					// Оставшиеся в очереди репозитории отмечаем как отмененные
+					for _, task := range group {
+						results <- models.CloneMsg{
+							Repo:     task.Repo,
+							Account:  task.Account.Name,
+							Action:   task.action(),
+							Canceled: true,
+							Err:      context.Canceled,
+						}
+					}
+				}
+			}
+		}()
+
+		go func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		var done models.CloneQueueDoneMsg
+		if len(tasks) > 0 {
+			done.Account = tasks[0].Account.Name
+		}
+		for result := range results {
+			switch {
+			case result.Canceled:
+				done.Canceled++
+			case result.Existing:
+				done.Existing++
+			case result.Success:
+				done.Cloned++
+			default:
+				done.Failed++
+			}
+			job.send(result)
+		}
+		job.finish(done)
+	}()
+	return job
+}
+
+// groupByPath объединяет задачи с одинаковым путем клона, сохраняя порядок очереди.
+// Иначе два git clone одноименных репозиториев разных владельцев шли бы в одну директорию.
+func (c *Client) groupByPath(tasks []CloneTask) [][]CloneTask {
+	var groups [][]CloneTask
+	index := map[string]int{}
+	for _, task := range tasks {
+		path, err := c.RepoPath(task.Account, task.Repo)
+		if err != nil {
+			// Ошибку пути вернет сама задача
+			groups = append(groups, []CloneTask{task})
+			continue
+		}
+		if i, ok := index[path]; ok {
+			groups[i] = append(groups[i], task)
+			continue
+		}
+		index[path] = len(groups)
+		groups = append(groups, []CloneTask{task})
+	}
+	return groups
+}
+
+// action возвращает действие задачи
+func (t CloneTask) action() string {
+	if t.Action == "" {
+		return models.ActionClone
+	}
+	return t.Action
+}
+
+// run выполняет задачу очереди
+func (c *Client) run(job *Job, task CloneTask) models.CloneMsg {
+	var msg models.CloneMsg
+	if task.action() != models.ActionClone {
+		msg = c.sync(job, task.Repo, task.Account, task.action())
+	} else {
+		msg = c.clone(job, task.Repo, task.Account, task.Protocol)
+	}
+	msg.Account = task.Account.Name
+	msg.Action = task.action()
+	return msg
+}
+
+// clone выполняет клонирование и возвращает итоговое сообщение
+func (c *Client) clone(job *Job, repo models.Repository, account models.Account, protocol string) models.CloneMsg {
+	job.progress(models.CloneProgressMsg{Repo: repo, Phase: "Starting"})
+	if protocol == "" {
+		protocol = account.Protocol()
+	}
+	if protocol == models.ProtocolHTTPS && account.Token == "" {
+		return models.CloneMsg{Repo: repo, Success: false, Err: fmt.Errorf("token is empty")}
+	}
+	if repo.Owner == "" || repo.Name == "" {
+		return models.CloneMsg{Repo: repo, Success: false, Err: fmt.Errorf("repository owner or name is empty")}
+	}
+
+	// Путь строится по шаблону из настроек, по умолчанию ~/develop/owner/repo-name
+	repoDir, err := c.RepoPath(account, repo)
+	if err != nil {
+		return models.CloneMsg{Repo: repo, Success: false, Err: err}
+	}
+	if err := os.MkdirAll(filepath.Dir(repoDir), utils.DefaultDirMode); err != nil {
+		return models.CloneMsg{Repo: repo, Success: false, Err: fmt.Errorf("failed to create clone directory: %v", err)}
+	}
+
+	// Если директория уже занята, проверяем, не склонирован ли в ней этот же репозиторий
+	if !dirEmpty(repoDir) {
+		return c.inspectExisting(repo, account, repoDir)
+	}
+
+	// Клонируем во временную директорию рядом и переносим ее на место после успеха,
+	// чтобы при ошибке удалять только то, что создал этот клон
+	tmpDir, err := os.MkdirTemp(filepath.Dir(repoDir), "."+filepath.Base(repoDir)+".clone-")
+	if err != nil {
+		return models.CloneMsg{Repo: repo, Success: false, Err: fmt.Errorf("failed to create clone directory: %v", err)}
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Токен передается через credential helper, поэтому в .git/config остается URL без учетных данных
+	cmd := cloneCommand(job.ctx, repo, account, protocol, tmpDir)
+	if output, err := runWithProgress(job, repo, cmd); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return models.CloneMsg{Repo: repo, Success: false, Canceled: true, Err: context.Canceled, Path: repoDir}
+		}
+		return models.CloneMsg{
+			Repo:    repo,
+			Success: false,
+			Err:     fmt.Errorf("git clone failed: %v, output: %s", err, ScrubSecrets(output, account.Token)),
+			Path:    repoDir,
+		}
+	}
+
+	// Пустая директория на месте клона заменяется; если ее успели заполнить, клон не переносится
+	os.Remove(repoDir)
+	if err := os.Rename(tmpDir, repoDir); err != nil {
+		return models.CloneMsg{Repo: repo, Success: false, Err: fmt.Errorf("%s was created while cloning", repoDir), Path: repoDir}
+	}
+
+	// Записываем git identity аккаунта в локальную конфигурацию репозитория
+	if err := ApplyIdentity(repoDir, account); err != nil {
+		return models.CloneMsg{
+			Repo:    repo,
+			Success: true,
+			Err:     fmt.Errorf("failed to apply git identity: %v", err),
+			Path:    repoDir,
+		}
+	}
+
+	return models.CloneMsg{
+		Repo:    repo,
+		Success: true,
+		Path:    repoDir,
+	}
+}
+
+// runWithProgress запускает команду git, передавая ее прогресс в TUI.
+// Возвращает вывод git без строк прогресса. При отмене задачи возвращает context.Canceled.
+func runWithProgress(job *Job, repo models.Repository, cmd *exec.Cmd) (string, error) {
+	cmd.WaitDelay = killDelay
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return "", err
+	}
+	if err := cmd.Start(); err != nil {
+		if errors.Is(job.ctx.Err(), context.Canceled) {
+			return "", context.Canceled
+		}
+		return "", fmt.Errorf("failed to start git: %v", err)
+	}
+
+	output := readProgress(stderr, func(phase string, percent int) {
+		job.progress(models.CloneProgressMsg{Repo: repo, Phase: phase, Percent: float64(percent) / 100})
+	})
+
+	if err := cmd.Wait(); err != nil {
+		if errors.Is(job.ctx.Err(), context.Canceled) {
+			return output, context.Canceled
+		}
+		return output, err
+	}
+	return output, nil
+}
+
+// readProgress читает stderr git, сообщает о прогрессе и возвращает
+// остальной вывод (без строк прогресса) для сообщения об ошибке
+func readProgress(r io.Reader, report func(phase string, percent int)) string {
+	var output strings.Builder
+	scanner := bufio.NewScanner(r)
+	scanner.Split(scanProgressLines)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if match := progressPattern.FindStringSubmatch(line); match != nil {
+			percent, _ := strconv.Atoi(match[2])
+			report(strings.TrimSpace(match[1]), percent)
+			continue
+		}
+		output.WriteString(line + "\n")
+	}
+	return strings.TrimSpace(output.String())
+}
+
+// scanProgressLines разбивает вывод git на строки по \n и \r
+func scanProgressLines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,372 @@
+package github
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KharpukhaevV/gitui/models"
+)
+
+// requireGit пропускает тест, если git не установлен
+func requireGit(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+}
+
+// runGit выполняет команду git и завершает тест при ошибке
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1",
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// newBareRepo создает bare-репозиторий с одним коммитом и возвращает модель
+// репозитория, clone_url которого указывает на него
+func newBareRepo(t *testing.T, owner, name string) models.Repository {
+	t.Helper()
+	root := t.TempDir()
+	bare := filepath.Join(root, owner, name+".git")
+	work := filepath.Join(root, "work")
+	if err := os.MkdirAll(bare, 0755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, bare, "init", "--bare", "--initial-branch=main")
+	runGit(t, root, "init", "--initial-branch=main", work)
+	if err := os.WriteFile(filepath.Join(work, "README.md"), []byte("# "+name+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, work, "add", "README.md")
+	runGit(t, work, "commit", "-m", "initial")
+	runGit(t, work, "push", bare, "main")
+
+	return models.Repository{
+		Owner:    owner,
+		Name:     name,
+		Host:     "github.example.com",
+		CloneURL: "file://" + bare,
+		SSHURL:   "git@github.example.com:" + owner + "/" + name + ".git",
+	}
+}
+
+// drainClone читает сообщения очереди и возвращает результаты репозиториев и итог очереди
+func drainClone(t *testing.T, job *Job) ([]models.CloneMsg, models.CloneQueueDoneMsg) {
+	t.Helper()
+	var results []models.CloneMsg
+	var done *models.CloneQueueDoneMsg
+	for {
+		msg := job.Wait()()
+		if msg == nil {
+			break
+		}
+		switch msg := msg.(type) {
+		case models.CloneMsg:
+			results = append(results, msg)
+		case models.CloneQueueDoneMsg:
+			done = &msg
+		case models.CloneProgressMsg:
+		default:
+			t.Fatalf("unexpected message %T", msg)
+		}
+	}
+	if done == nil {
+		t.Fatal("queue closed without CloneQueueDoneMsg")
+	}
+	return results, *done
+}
+
+// cloneOne клонирует репозиторий и возвращает его результат
+func cloneOne(t *testing.T, c *Client, repo models.Repository, account models.Account) (models.CloneMsg, models.CloneQueueDoneMsg) {
+	t.Helper()
+	results, done := drainClone(t, c.CloneRepo(repo, account, models.ProtocolHTTPS))
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	return results[0], done
+}
+
+func TestCloneRepo(t *testing.T) {
+	requireGit(t)
+	c := newTestClient(t)
+	repo := newBareRepo(t, "octocat", "hello")
+	account := models.Account{Name: "work", Token: "secret", GitName: "Octo Cat", GitEmail: "octo@example.com"}
+
+	result, done := cloneOne(t, c, repo, account)
+	if !result.Success || result.Err != nil {
+		t.Fatalf("clone failed: %+v", result)
+	}
+	wantPath := filepath.Join(c.Settings.RootFor(account), "octocat", "hello")
+	if result.Path != wantPath {
+		t.Errorf("Path = %q, want %q", result.Path, wantPath)
+	}
+	if result.Account != "work" || result.Action != models.ActionClone {
+		t.Errorf("Account = %q, Action = %q", result.Account, result.Action)
+	}
+	if done != (models.CloneQueueDoneMsg{Account: "work", Cloned: 1}) {
+		t.Errorf("done = %+v", done)
+	}
+
+	if _, err := os.Stat(filepath.Join(result.Path, "README.md")); err != nil {
+		t.Errorf("README.md not cloned: %v", err)
+	}
+	if got := runGit(t, result.Path, "config", "--local", "user.email"); got != "octo@example.com" {
+		t.Errorf("user.email = %q, want the account identity", got)
+	}
+	// Токен не должен попасть в конфигурацию клона
+	config, err := os.ReadFile(filepath.Join(result.Path, ".git", "config"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(config), "secret") {
+		t.Error("token stored in .git/config")
+	}
+
+	if cloned := c.ClonedRepos([]models.Repository{repo, {Owner: "octocat", Name: "other"}}, account); len(cloned) != 1 {
+		t.Errorf("ClonedRepos = %d repos, want 1", len(cloned))
+	}
+}
+
+func TestCloneRepoAlreadyExists(t *testing.T) {
+	requireGit(t)
+	c := newTestClient(t)
+	repo := newBareRepo(t, "octocat", "hello")
+	account := models.Account{Name: "work", Token: "secret"}
+
+	if result, _ := cloneOne(t, c, repo, account); !result.Success {
+		t.Fatalf("first clone failed: %+v", result)
+	}
+	result, done := cloneOne(t, c, repo, account)
+	if !result.Existing || result.Success || result.Err != nil {
+		t.Errorf("second clone = %+v, want Existing", result)
+	}
+	if done.Existing != 1 || done.Cloned != 0 || done.Failed != 0 {
+		t.Errorf("done = %+v, want one existing", done)
+	}
+
+	// Уже склонированный репозиторий можно обновить через ту же очередь
+	results, done := drainClone(t, c.CloneQueue([]CloneTask{{Repo: repo, Account: account, Action: models.ActionFetch}}))
+	if len(results) != 1 || !results[0].Success || results[0].Action != models.ActionFetch {
+		t.Errorf("fetch results = %+v", results)
+	}
+	if done.Cloned != 1 {
+		t.Errorf("done = %+v, want one synced", done)
+	}
+}
+
+func TestCloneRepoOccupiedDirectory(t *testing.T) {
+	requireGit(t)
+	account := models.Account{Name: "work", Token: "secret"}
+
+	t.Run("different remote", func(t *testing.T) {
+		c := newTestClient(t)
+		repo := newBareRepo(t, "octocat", "hello")
+		other := newBareRepo(t, "octocat", "other")
+		path, _ := c.Settings.RepoPath(account, repo)
+		runGit(t, t.TempDir(), "clone", other.CloneURL, path)
+
+		result, done := cloneOne(t, c, repo, account)
+		if result.Success || result.Existing || result.Err == nil || !strings.Contains(result.Err.Error(), "different remote") {
+			t.Errorf("result = %+v, want a different remote error", result)
+		}
+		if done.Failed != 1 {
+			t.Errorf("done = %+v, want one failed", done)
+		}
+	})
+
+	t.Run("not a git repository", func(t *testing.T) {
+		c := newTestClient(t)
+		repo := newBareRepo(t, "octocat", "hello")
+		path, _ := c.Settings.RepoPath(account, repo)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "notes.txt"), []byte("keep me"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, _ := cloneOne(t, c, repo, account)
+		if result.Success || result.Err == nil || !strings.Contains(result.Err.Error(), "not a git repository") {
+			t.Errorf("result = %+v, want a not a git repository error", result)
+		}
+		if _, err := os.Stat(filepath.Join(path, "notes.txt")); err != nil {
+			t.Errorf("existing file removed: %v", err)
+		}
+	})
+
+	t.Run("empty directory", func(t *testing.T) {
+		c := newTestClient(t)
+		repo := newBareRepo(t, "octocat", "hello")
+		path, _ := c.Settings.RepoPath(account, repo)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if result, _ := cloneOne(t, c, repo, account); !result.Success {
+			t.Errorf("clone into an empty directory failed: %+v", result)
+		}
+	})
+}
+
+func TestCloneRepoErrors(t *testing.T) {
+	requireGit(t)
+
+	t.Run("missing remote", func(t *testing.T) {
+		c := newTestClient(t)
+		account := models.Account{Name: "work", Token: "secret"}
+		repo := models.Repository{Owner: "octocat", Name: "missing", CloneURL: "file://" + filepath.Join(t.TempDir(), "missing.git")}
+
+		result, done := cloneOne(t, c, repo, account)
+		if result.Success || result.Err == nil || !strings.Contains(result.Err.Error(), "git clone failed") {
+			t.Errorf("result = %+v, want a clone error", result)
+		}
+		if strings.Contains(result.Err.Error(), "secret") {
+			t.Error("token leaked into the error")
+		}
+		if _, err := os.Stat(result.Path); !os.IsNotExist(err) {
+			t.Errorf("partial clone directory left behind: %v", err)
+		}
+		if done.Failed != 1 {
+			t.Errorf("done = %+v, want one failed", done)
+		}
+		if entries, _ := os.ReadDir(filepath.Dir(result.Path)); len(entries) != 0 {
+			t.Errorf("temporary clone directory left behind: %v", entries)
+		}
+	})
+
+	t.Run("keeps existing directory", func(t *testing.T) {
+		c := newTestClient(t)
+		account := models.Account{Name: "work", Token: "secret"}
+		repo := models.Repository{Owner: "octocat", Name: "missing", CloneURL: "file://" + filepath.Join(t.TempDir(), "missing.git")}
+		path, _ := c.Settings.RepoPath(account, repo)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		// Директорию создал не этот клон, поэтому после ошибки она остается
+		if result, _ := cloneOne(t, c, repo, account); result.Success || result.Err == nil {
+			t.Fatalf("result = %+v, want a clone error", result)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("existing directory removed: %v", err)
+		}
+	})
+
+	t.Run("empty token", func(t *testing.T) {
+		c := newTestClient(t)
+		repo := newBareRepo(t, "octocat", "hello")
+		result, _ := cloneOne(t, c, repo, models.Account{Name: "work"})
+		if result.Success || result.Err == nil || !strings.Contains(result.Err.Error(), "token is empty") {
+			t.Errorf("result = %+v, want an empty token error", result)
+		}
+	})
+
+	t.Run("empty name", func(t *testing.T) {
+		c := newTestClient(t)
+		result, _ := cloneOne(t, c, models.Repository{Owner: "octocat"}, models.Account{Name: "work", Token: "secret"})
+		if result.Success || result.Err == nil {
+			t.Errorf("result = %+v, want an error", result)
+		}
+	})
+}
+
+func TestRepoPathLegacyClone(t *testing.T) {
+	requireGit(t)
+	c := newTestClient(t)
+	c.Settings.CloneTemplate = ""
+	account := models.Account{Name: "work", Token: "secret"}
+	repo := newBareRepo(t, "octocat", "hello")
+	other := newBareRepo(t, "acme", "hello")
+
+	// Клон по прежнему шаблону {root}/{name}
+	legacy, _ := c.Settings.LegacyRepoPath(account, repo)
+	runGit(t, t.TempDir(), "clone", repo.CloneURL, legacy)
+
+	if path, err := c.RepoPath(account, repo); err != nil || path != legacy {
+		t.Errorf("RepoPath = %q, %v, want the legacy clone %q", path, err, legacy)
+	}
+	if result, _ := cloneOne(t, c, repo, account); !result.Existing || result.Path != legacy {
+		t.Errorf("result = %+v, want the existing legacy clone", result)
+	}
+
+	// Одноименный репозиторий другого владельца клонируется по новому шаблону
+	want, _ := c.Settings.RepoPath(account, other)
+	result, _ := cloneOne(t, c, other, account)
+	if !result.Success || result.Path != want || want == legacy {
+		t.Errorf("result = %+v, want a clone at %q", result, want)
+	}
+	if cloned := c.ClonedRepos([]models.Repository{repo, other}, account); len(cloned) != 2 {
+		t.Errorf("ClonedRepos = %d repos, want 2", len(cloned))
+	}
+}
+
+func TestCloneQueueSamePath(t *testing.T) {
+	requireGit(t)
+	account := models.Account{Name: "work", Token: "secret"}
+	alice := newBareRepo(t, "alice", "tool")
+	acme := newBareRepo(t, "acme", "tool")
+
+	// Без {owner} в шаблоне оба репозитория клонируются в одну директорию.
+	// Повторяем, чтобы параллельные воркеры успели столкнуться.
+	for i := 0; i < 10; i++ {
+		c := newTestClient(t)
+		c.Settings.CloneTemplate = "{root}/{name}"
+		results, done := drainClone(t, c.CloneQueue([]CloneTask{{Repo: alice, Account: account}, {Repo: acme, Account: account}}))
+		if len(results) != 2 || done.Cloned != 1 || done.Failed != 1 {
+			t.Fatalf("results = %+v, done = %+v, want one cloned and one failed", results, done)
+		}
+
+		// Задачи с одним путем выполняются по порядку очереди
+		winner, loser := results[0], results[1]
+		if !winner.Success || winner.Repo.Title() != "alice/tool" {
+			t.Errorf("first clone = %+v, want alice/tool cloned", winner)
+		}
+		if loser.Err == nil || !strings.Contains(loser.Err.Error(), "different remote") {
+			t.Errorf("second clone = %+v, want a different remote error", loser)
+		}
+		if err := checkRemote(winner.Path, winner.Repo, account); err != nil {
+			t.Errorf("clone of %s is missing or broken: %v", winner.Repo.Title(), err)
+		}
+	}
+}
+
+func TestCloneQueueCancel(t *testing.T) {
+	requireGit(t)
+	c := newTestClient(t)
+	c.Settings.CloneWorkers = 1
+	account := models.Account{Name: "work", Token: "secret"}
+	repo := newBareRepo(t, "acme", "tool")
+
+	var tasks []CloneTask
+	for i := 0; i < 5; i++ {
+		task := CloneTask{Repo: repo, Account: account}
+		task.Repo.Name = fmt.Sprintf("tool-%d", i)
+		tasks = append(tasks, task)
+	}
+	job := c.CloneQueue(tasks)
+	job.Cancel()
+
+	// Отмененные до запуска задачи отмечаются аккаунтом и действием, как и выполненные
+	results, done := drainClone(t, job)
+	if len(results) != len(tasks) || done.Canceled == 0 {
+		t.Fatalf("results = %+v, done = %+v", results, done)
+	}
+	for _, result := range results {
+		if result.Account != "work" || result.Action != models.ActionClone {
+			t.Errorf("result %s: account = %q, action = %q", result.Repo.Title(), result.Account, result.Action)
+		}
+	}
+}
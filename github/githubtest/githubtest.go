@@ -0,0 +1,164 @@
+// Package githubtest предоставляет фейковый GitHub API для тестов пакетов,
+// которые работают с GitHub через github.Client.
+package githubtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KharpukhaevV/gitui/models"
+)
+
+// Token токен, который фейковый API ожидает в запросах списка репозиториев
+const Token = "secret"
+
+// RateReset время сброса лимитов, которое возвращает фейковый API
+var RateReset = time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// Repo возвращает репозиторий в формате ответа GitHub API
+func Repo(owner, name string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":             name,
+		"full_name":        owner + "/" + name,
+		"owner":            map[string]interface{}{"login": owner},
+		"description":      "repo " + name,
+		"language":         "Go",
+		"stargazers_count": 3,
+		"private":          true,
+		"clone_url":        "https://github.example.com/" + owner + "/" + name + ".git",
+		"ssh_url":          "git@github.example.com:" + owner + "/" + name + ".git",
+		"updated_at":       "2024-05-01T10:00:00Z",
+		"topics":           []string{"cli"},
+		"license":          map[string]interface{}{"spdx_id": "MIT", "name": "MIT License"},
+	}
+}
+
+// Server фейковый GitHub API со списком репозиториев пользователя, разбитым на страницы.
+// URL подходит для поля Account.APIURL.
+type Server struct {
+	URL string
+
+	t        *testing.T
+	server   *httptest.Server
+	mu       sync.Mutex
+	pages    [][]map[string]interface{}
+	status   int               // код ответа вместо списка, если не 0
+	header   map[string]string // заголовки ответа с ошибкой
+	requests map[int]int       // количество запросов каждой страницы
+}
+
+// NewServer запускает фейковый API с указанными страницами репозиториев.
+// Сервер останавливается после завершения теста.
+func NewServer(t *testing.T, pages ...[]map[string]interface{}) *Server {
+	s := &Server{t: t, pages: pages, requests: map[int]int{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/user/repos", s.handleRepos)
+	mux.HandleFunc("/api/v3/user", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-OAuth-Scopes", "repo, read:org")
+		writeJSON(w, map[string]interface{}{"login": "octocat", "avatar_url": "https://example.com/a.png"})
+	})
+	mux.HandleFunc("/api/v3/rate_limit", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"resources": map[string]interface{}{
+			"core":   map[string]interface{}{"limit": 5000, "remaining": 4990, "reset": RateReset.Unix()},
+			"search": map[string]interface{}{"limit": 30, "remaining": 30, "reset": RateReset.Unix()},
+		}})
+	})
+	s.server = httptest.NewServer(mux)
+	s.URL = s.server.URL
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+// handleRepos отдает страницу списка с заголовком Link и ETag,
+// а на условный запрос с тем же ETag отвечает 304
+func (s *Server) handleRepos(w http.ResponseWriter, r *http.Request) {
+	if auth := r.Header.Get("Authorization"); auth != "Bearer "+Token {
+		s.t.Errorf("Authorization = %q, want the account token", auth)
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page == 0 {
+		page = 1
+	}
+
+	s.mu.Lock()
+	s.requests[page]++
+	status, header, pages := s.status, s.header, s.pages
+	s.mu.Unlock()
+
+	if status != 0 {
+		for key, value := range header {
+			w.Header().Set(key, value)
+		}
+		w.WriteHeader(status)
+		writeJSON(w, map[string]interface{}{"message": http.StatusText(status)})
+		return
+	}
+	if page > len(pages) {
+		writeJSON(w, []interface{}{})
+		return
+	}
+
+	etag := fmt.Sprintf(`"page-%d-%d"`, page, len(pages[page-1]))
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	var links []string
+	link := func(rel string, n int) {
+		u := *r.URL
+		q := u.Query()
+		q.Set("page", strconv.Itoa(n))
+		u.RawQuery = q.Encode()
+		links = append(links, fmt.Sprintf(`<%s%s>; rel="%s"`, s.URL, u.String(), rel))
+	}
+	if page < len(pages) {
+		link("next", page+1)
+		link("last", len(pages))
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+	w.Header().Set("ETag", etag)
+	writeJSON(w, pages[page-1])
+}
+
+// Fail переводит API в режим ответа с ошибкой
+func (s *Server) Fail(status int, header map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+	s.header = header
+}
+
+// PageRequests возвращает количество запросов страницы
+func (s *Server) PageRequests(page int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[page]
+}
+
+// Account возвращает аккаунт work, подключенный к фейковому API
+func (s *Server) Account(t *testing.T) models.Account {
+	t.Helper()
+	account := models.Account{Name: "work", Token: Token, APIURL: s.URL}
+	if err := account.Connect(); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	return account
+}
+
+// writeJSON пишет значение в ответ в формате JSON
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	data, _ := json.Marshal(value)
+	w.Write(data)
+}
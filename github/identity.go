@@ -0,0 +1,79 @@
+package github
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/KharpukhaevV/gitui/models"
+	"github.com/KharpukhaevV/gitui/utils"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ApplyIdentity записывает git identity аккаунта в локальную конфигурацию репозитория
+func ApplyIdentity(repoDir string, account models.Account) error {
+	if !account.HasIdentity() {
+		return nil
+	}
+
+	settings := [][2]string{}
+	if account.GitName != "" {
+		settings = append(settings, [2]string{"user.name", account.GitName})
+	}
+	if account.GitEmail != "" {
+		settings = append(settings, [2]string{"user.email", account.GitEmail})
+	}
+	if account.SigningKey != "" {
+		key := account.SigningKey
+		// Ключ SSH задается путем к .pub файлу или строкой "ssh-..."
+		if strings.HasPrefix(key, "ssh-") || strings.HasSuffix(key, ".pub") {
+			settings = append(settings, [2]string{"gpg.format", "ssh"})
+			key = utils.ExpandHome(key)
+		}
+		settings = append(settings,
+			[2]string{"user.signingkey", key},
+			[2]string{"commit.gpgsign", "true"},
+			[2]string{"tag.gpgsign", "true"},
+		)
+	}
+
+	for _, setting := range settings {
+		output, err := exec.Command("git", "-C", repoDir, "config", "--local", setting[0], setting[1]).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("git config %s failed: %v, output: %s", setting[0], err, strings.TrimSpace(string(output)))
+		}
+	}
+	return nil
+}
+
+// ApplyIdentityToCloned применяет git identity аккаунта ко всем уже склонированным репозиториям.
+// Клоны с другим remote (например, одноименный репозиторий другого владельца) пропускаются.
+func (c *Client) ApplyIdentityToCloned(repos []models.Repository, account models.Account) tea.Cmd {
+	return func() tea.Msg {
+		if !account.HasIdentity() {
+			return models.IdentityAppliedMsg{Err: fmt.Errorf("account %s has no git identity configured", account.Name)}
+		}
+
+		applied, skipped := 0, 0
+		for _, repo := range repos {
+			repoDir, err := c.RepoPath(account, repo)
+			if err != nil {
+				return models.IdentityAppliedMsg{Applied: applied, Skipped: skipped, Err: err}
+			}
+			if _, err := os.Stat(filepath.Join(repoDir, ".git")); err != nil {
+				continue
+			}
+			if err := checkRemote(repoDir, repo, account); err != nil {
+				skipped++
+				continue
+			}
+			if err := ApplyIdentity(repoDir, account); err != nil {
+				return models.IdentityAppliedMsg{Applied: applied, Skipped: skipped, Err: fmt.Errorf("%s: %v", repo.Name, err)}
+			}
+			applied++
+		}
+		return models.IdentityAppliedMsg{Applied: applied, Skipped: skipped}
+	}
+}
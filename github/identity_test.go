@@ -0,0 +1,39 @@
+package github
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/KharpukhaevV/gitui/models"
+)
+
+func TestApplyIdentityToClonedSkipsOtherRemote(t *testing.T) {
+	requireGit(t)
+	c := newTestClient(t)
+	c.Settings.CloneTemplate = "{root}/{name}"
+	personal := newBareRepo(t, "alice", "tool")
+	work := newBareRepo(t, "acme", "tool")
+
+	if result, _ := cloneOne(t, c, personal, models.Account{Name: "personal", Token: "secret"}); !result.Success {
+		t.Fatalf("clone failed: %+v", result)
+	}
+
+	// По пути acme/tool склонирован alice/tool: identity рабочего аккаунта туда не пишется
+	account := models.Account{Name: "work", Token: "secret", GitEmail: "alice@acme.example", SigningKey: "ABCDEF"}
+	msg := c.ApplyIdentityToCloned([]models.Repository{work}, account)().(models.IdentityAppliedMsg)
+	if msg.Err != nil || msg.Applied != 0 || msg.Skipped != 1 {
+		t.Errorf("msg = %+v, want one skipped", msg)
+	}
+	path, _ := c.Settings.RepoPath(account, work)
+	if output, err := exec.Command("git", "-C", path, "config", "--local", "user.signingkey").Output(); err == nil {
+		t.Errorf("signing key written into another repository: %s", output)
+	}
+
+	msg = c.ApplyIdentityToCloned([]models.Repository{personal}, account)().(models.IdentityAppliedMsg)
+	if msg.Err != nil || msg.Applied != 1 || msg.Skipped != 0 {
+		t.Errorf("msg = %+v, want one applied", msg)
+	}
+	if got := runGit(t, path, "config", "--local", "user.email"); got != "alice@acme.example" {
+		t.Errorf("user.email = %q", got)
+	}
+}
@@ -0,0 +1,95 @@
+package github
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Job фоновая операция, которая передает сообщения в TUI через канал.
+// После каждого полученного сообщения нужно снова вызвать Wait, пока задача не завершится.
+type Job struct {
+	msgs   chan tea.Msg
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// newJob создает задачу с отменяемым контекстом
+func newJob() *Job {
+	return newJobContext(context.Background())
+}
+
+// newJobContext создает задачу, которая отменяется вместе с родительским контекстом
+func newJobContext(parent context.Context) *Job {
+	ctx, cancel := context.WithCancel(parent)
+	return &Job{
+		msgs:   make(chan tea.Msg, 16),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// NewJob создает завершенную задачу, которая передает сообщения msgs.
+// Нужна реализациям Provider, которые получают результат сразу (например, в тестах).
+func NewJob(msgs ...tea.Msg) *Job {
+	job := newJob()
+	job.msgs = make(chan tea.Msg, len(msgs))
+	for _, msg := range msgs {
+		job.msgs <- msg
+	}
+	job.close()
+	return job
+}
+
+// Wait возвращает команду, ожидающую следующее сообщение задачи.
+// Когда задача завершена, команда возвращает nil.
+func (j *Job) Wait() tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-j.msgs
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// Cancel отменяет задачу
+func (j *Job) Cancel() {
+	j.cancel()
+}
+
+// progress передает промежуточное сообщение в TUI. Если TUI не успевает
+// их обрабатывать, сообщение отбрасывается, чтобы не блокировать задачу.
+func (j *Job) progress(msg tea.Msg) {
+	select {
+	case j.msgs <- msg:
+	default:
+	}
+}
+
+// send передает сообщение в TUI, дожидаясь его получения
+func (j *Job) send(msg tea.Msg) {
+	j.msgs <- msg
+}
+
+// deliver передает сообщение в TUI, пока задача не отменена. После отмены
+// сообщение отбрасывается: TUI больше не ждет результатов этой задачи.
+func (j *Job) deliver(msg tea.Msg) {
+	select {
+	case j.msgs <- msg:
+	case <-j.ctx.Done():
+	}
+}
+
+// close закрывает канал задачи
+func (j *Job) close() {
+	close(j.msgs)
+	j.cancel()
+}
+
+// finish передает последнее сообщение и закрывает канал задачи
+func (j *Job) finish(msg tea.Msg) {
+	j.msgs <- msg
+	close(j.msgs)
+	j.cancel()
+}
@@ -0,0 +1,83 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/KharpukhaevV/gitui/models"
+)
+
+// RepoPath возвращает путь клона репозитория. Если шаблон в настройках не задан,
+// а по новому пути клона нет, используется клон по прежнему пути {root}/{name},
+// но только если в нем склонирован этот же репозиторий.
+func (c *Client) RepoPath(account models.Account, repo models.Repository) (string, error) {
+	repoDir, err := c.Settings.RepoPath(account, repo)
+	if err != nil || !dirEmpty(repoDir) {
+		return repoDir, err
+	}
+	if legacy, ok := c.Settings.LegacyRepoPath(account, repo); ok && checkRemote(legacy, repo, account) == nil {
+		return legacy, nil
+	}
+	return repoDir, nil
+}
+
+// dirEmpty сообщает, что директории нет или она пуста
+func dirEmpty(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return true
+	}
+	return err == nil && len(entries) == 0
+}
+
+// checkRemote проверяет, что в директории склонирован указанный репозиторий
+func checkRemote(repoDir string, repo models.Repository, account models.Account) error {
+	if _, err := os.Stat(filepath.Join(repoDir, ".git")); err != nil {
+		return fmt.Errorf("%s exists and is not a git repository", repoDir)
+	}
+
+	output, err := exec.Command("git", "-C", repoDir, "remote", "get-url", "origin").Output()
+	if err != nil {
+		return fmt.Errorf("%s is a git repository without an origin remote", repoDir)
+	}
+	remote := strings.TrimSpace(string(output))
+	if !sameRemote(remote, repo, account) {
+		return fmt.Errorf("%s points at a different remote: %s", repoDir, ScrubSecrets(remote))
+	}
+	return nil
+}
+
+// sameRemote сравнивает URL remote с HTTPS и SSH адресами репозитория,
+// включая SSH адрес с псевдонимом хоста аккаунта
+func sameRemote(remote string, repo models.Repository, account models.Account) bool {
+	host, path := parseRemote(remote)
+	for _, candidate := range []string{httpsURL(repo), sshURL(repo, models.Account{}), sshURL(repo, account)} {
+		candidateHost, candidatePath := parseRemote(candidate)
+		if strings.EqualFold(host, candidateHost) && strings.EqualFold(path, candidatePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRemote возвращает хост и путь репозитория (owner/name) из URL remote.
+// Поддерживаются URL со схемой и scp-подобная запись git@host:owner/name.git.
+func parseRemote(remote string) (string, string) {
+	var host, path string
+	if u, err := url.Parse(remote); err == nil && u.Scheme != "" {
+		host, path = u.Hostname(), u.Path
+	} else if colon := strings.Index(remote, ":"); colon >= 0 {
+		host, path = remote[:colon], remote[colon+1:]
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+	} else {
+		path = remote
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	return host, path
+}
@@ -0,0 +1,33 @@
+package github
+
+import (
+	"context"
+	"time"
+
+	"github.com/KharpukhaevV/gitui/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Provider доступ к GitHub и локальным клонам, который использует TUI.
+// Client реализует его через GitHub API и git; в тестах его можно заменить.
+type Provider interface {
+	ValidateAccount(ctx context.Context, request int, account models.Account) tea.Cmd
+	LoadRepos(ctx context.Context, request int, account models.Account, owner models.Owner) *Job
+	LoadAllRepos(ctx context.Context, request int, accounts []models.Account) *Job
+	CachedRepos(account models.Account, owner models.Owner) ([]models.Repository, time.Time, bool)
+	CachedAllRepos(accounts []models.Account) ([]models.Repository, time.Time, bool)
+	ForgetAccount(account models.Account)
+	RenameAccount(account models.Account, name string)
+	LoadOwners(ctx context.Context, request int, account models.Account) tea.Cmd
+	LoadReadme(ctx context.Context, request int, account models.Account, repo models.Repository) tea.Cmd
+	RateLimit(account models.Account) (models.RateLimit, bool)
+	RateLimits(ctx context.Context, account models.Account) tea.Cmd
+	CloneQueue(tasks []CloneTask) *Job
+	RepoPath(account models.Account, repo models.Repository) (string, error)
+	ClonedRepos(repos []models.Repository, account models.Account) []models.Repository
+	ScanLocal(ctx context.Context, scan int, repos []models.Repository, account models.Account) *Job
+	ApplyIdentityToCloned(repos []models.Repository, account models.Account) tea.Cmd
+}
+
+// Client должен реализовывать Provider
+var _ Provider = (*Client)(nil)
@@ -0,0 +1,120 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KharpukhaevV/gitui/models"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-github/github"
+)
+
+// secondaryBackoff пауза после вторичного лимита, если GitHub не прислал Retry-After
+const secondaryBackoff = time.Minute
+
+// RateLimit возвращает последнее известное состояние лимита запросов аккаунта
+func (c *Client) RateLimit(account models.Account) (models.RateLimit, bool) {
+	c.ratesMu.Lock()
+	defer c.ratesMu.Unlock()
+	rate, ok := c.rates[account.Name]
+	return rate, ok
+}
+
+// recordRate запоминает лимит запросов из ответа API
+func (c *Client) recordRate(account models.Account, resp *github.Response) {
+	if resp == nil || resp.Rate.Limit == 0 {
+		return
+	}
+	c.setRate(account, resp.Rate)
+}
+
+// setRate сохраняет лимит запросов аккаунта
+func (c *Client) setRate(account models.Account, rate github.Rate) {
+	c.ratesMu.Lock()
+	defer c.ratesMu.Unlock()
+	c.rates[account.Name] = models.RateLimit{
+		Limit:     rate.Limit,
+		Remaining: rate.Remaining,
+		Reset:     rate.Reset.Time,
+	}
+}
+
+// RateLimits запрашивает текущие лимиты аккаунта (запрос /rate_limit не расходует лимит)
+func (c *Client) RateLimits(ctx context.Context, account models.Account) tea.Cmd {
+	return func() tea.Msg {
+		if account.Client == nil {
+			return models.RateLimitMsg{Account: account.Name, Err: errors.New("GitHub client not initialized")}
+		}
+		limits, _, err := account.Client.RateLimits(ctx)
+		if err != nil {
+			return models.RateLimitMsg{Account: account.Name, Err: err}
+		}
+
+		msg := models.RateLimitMsg{Account: account.Name}
+		if limits.Core != nil {
+			c.setRate(account, *limits.Core)
+			msg.Core, _ = c.RateLimit(account)
+		}
+		if limits.Search != nil {
+			msg.Search = models.RateLimit{
+				Limit:     limits.Search.Limit,
+				Remaining: limits.Search.Remaining,
+				Reset:     limits.Search.Reset.Time,
+			}
+		}
+		return msg
+	}
+}
+
+// rateLimitRetry определяет, что запрос отклонен из-за первичного или вторичного
+// лимита, и возвращает время, после которого его можно повторить
+func (c *Client) rateLimitRetry(account models.Account, err error) (time.Time, bool) {
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		c.setRate(account, rateErr.Rate)
+		return laterOf(rateErr.Rate.Reset.Time), true
+	}
+
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		if abuseErr.RetryAfter != nil {
+			return laterOf(time.Now().Add(*abuseErr.RetryAfter)), true
+		}
+		return time.Now().Add(secondaryBackoff), true
+	}
+
+	// go-github распознает лимиты по тексту ответа и документации, поэтому
+	// проверяем и заголовки: исчерпанный первичный лимит и Retry-After вторичного
+	var respErr *github.ErrorResponse
+	if errors.As(err, &respErr) && respErr.Response != nil {
+		status := respErr.Response.StatusCode
+		if status != http.StatusForbidden && status != http.StatusTooManyRequests {
+			return time.Time{}, false
+		}
+		header := respErr.Response.Header
+		if header.Get("X-RateLimit-Remaining") == "0" {
+			if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+				return laterOf(time.Unix(reset, 0)), true
+			}
+		}
+		if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil {
+			return laterOf(time.Now().Add(time.Duration(seconds) * time.Second)), true
+		}
+		if strings.Contains(strings.ToLower(respErr.Message), "rate limit") {
+			return time.Now().Add(secondaryBackoff), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// laterOf возвращает время повтора не раньше чем через секунду
+func laterOf(t time.Time) time.Time {
+	if min := time.Now().Add(time.Second); t.Before(min) {
+		return min
+	}
+	return t
+}
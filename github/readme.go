@@ -0,0 +1,52 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/KharpukhaevV/gitui/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// LoadReadme загружает README репозитория. Загруженные README запоминаются
+// до конца работы программы, повторное открытие не обращается к API.
+func (c *Client) LoadReadme(ctx context.Context, request int, account models.Account, repo models.Repository) tea.Cmd {
+	return func() tea.Msg {
+		msg := models.ReadmeLoadedMsg{Request: request, Repo: repo}
+		if content, ok := c.cachedReadme(repo); ok {
+			msg.Content = content
+			return msg
+		}
+		if account.Client == nil {
+			msg.Err = errors.New("GitHub client not initialized")
+			return msg
+		}
+
+		readme, resp, err := account.Client.Repositories.GetReadme(ctx, repo.Owner, repo.Name, nil)
+		c.recordRate(account, resp)
+		if err != nil {
+			// Репозиторий без README — не ошибка
+			if resp == nil || resp.StatusCode != http.StatusNotFound {
+				msg.Err = err
+				return msg
+			}
+		} else if msg.Content, err = readme.GetContent(); err != nil {
+			msg.Err = err
+			return msg
+		}
+
+		c.readmeMu.Lock()
+		c.readmes[repo.Key()] = msg.Content
+		c.readmeMu.Unlock()
+		return msg
+	}
+}
+
+// cachedReadme возвращает ранее загруженный README репозитория
+func (c *Client) cachedReadme(repo models.Repository) (string, bool) {
+	c.readmeMu.Lock()
+	defer c.readmeMu.Unlock()
+	content, ok := c.readmes[repo.Key()]
+	return content, ok
+}
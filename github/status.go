@@ -0,0 +1,99 @@
+package github
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/KharpukhaevV/gitui/models"
+	"github.com/KharpukhaevV/gitui/utils"
+)
+
+// ScanLocal проверяет локальные клоны репозиториев в фоне пулом из Settings.Workers()
+// воркеров. Задача передает LocalStatusMsg для каждого репозитория и завершается
+// после проверки всех. После отмены ctx оставшиеся проверки пропускаются.
+func (c *Client) ScanLocal(ctx context.Context, scan int, repos []models.Repository, account models.Account) *Job {
+	job := newJobContext(ctx)
+	go func() {
+		defer job.close()
+		queue := make(chan models.Repository)
+
+		var wg sync.WaitGroup
+		for i := 0; i < utils.Min(c.Settings.Workers(), len(repos)); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for repo := range queue {
+					status := c.LocalStatus(job.ctx, repo, account)
+					if job.ctx.Err() == nil {
+						job.deliver(models.LocalStatusMsg{Scan: scan, Repo: repo, Status: status})
+					}
+				}
+			}()
+		}
+
+	feed:
+		for _, repo := range repos {
+			select {
+			case queue <- repo:
+			case <-job.ctx.Done():
+				break feed
+			}
+		}
+		close(queue)
+		wg.Wait()
+	}()
+	return job
+}
+
+// LocalStatus возвращает состояние локального клона репозитория:
+// текущую ветку, наличие изменений и расхождение с upstream.
+// Если по пути клона склонирован другой репозиторий, его состояние не показывается.
+func (c *Client) LocalStatus(ctx context.Context, repo models.Repository, account models.Account) models.LocalStatus {
+	repoDir, err := c.RepoPath(account, repo)
+	if err != nil {
+		return models.LocalStatus{}
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, ".git")); err != nil {
+		return models.LocalStatus{}
+	}
+	if err := checkRemote(repoDir, repo, account); err != nil {
+		return models.LocalStatus{Occupied: true, Err: err}
+	}
+
+	// --no-optional-locks не дает git status обновлять индекс и мешать работе пользователя
+	output, err := exec.CommandContext(ctx, "git", "--no-optional-locks", "-C", repoDir,
+		"status", "--porcelain=v2", "--branch").Output()
+	if err != nil {
+		return models.LocalStatus{Cloned: true, Err: fmt.Errorf("git status failed: %v", err)}
+	}
+	return parseStatus(output)
+}
+
+// parseStatus разбирает вывод git status --porcelain=v2 --branch
+func parseStatus(output []byte) models.LocalStatus {
+	status := models.LocalStatus{Cloned: true}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "# branch.head "):
+			status.Branch = strings.TrimPrefix(line, "# branch.head ")
+		case strings.HasPrefix(line, "# branch.upstream "):
+			status.Upstream = true
+		case strings.HasPrefix(line, "# branch.ab "):
+			// # branch.ab +<ahead> -<behind>
+			fmt.Sscanf(strings.TrimPrefix(line, "# branch.ab "), "+%d -%d", &status.Ahead, &status.Behind)
+		case strings.HasPrefix(line, "#"):
+		case line != "":
+			status.Dirty = true
+		}
+	}
+	return status
+}
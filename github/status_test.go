@@ -0,0 +1,81 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KharpukhaevV/gitui/models"
+)
+
+func TestLocalStatus(t *testing.T) {
+	requireGit(t)
+	c := newTestClient(t)
+	c.Settings.CloneTemplate = "{root}/{name}"
+	account := models.Account{Name: "work", Token: "secret"}
+	alice := newBareRepo(t, "alice", "tool")
+	acme := newBareRepo(t, "acme", "tool")
+
+	if status := c.LocalStatus(context.Background(), alice, account); status.Cloned || status.Occupied {
+		t.Errorf("status before clone = %+v, want not cloned", status)
+	}
+
+	result, _ := cloneOne(t, c, alice, account)
+	if !result.Success {
+		t.Fatalf("clone failed: %+v", result)
+	}
+	if err := os.WriteFile(filepath.Join(result.Path, "notes.txt"), []byte("draft"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	status := c.LocalStatus(context.Background(), alice, account)
+	if !status.Cloned || status.Occupied || status.Branch != "main" || !status.Dirty || !status.Upstream || status.Err != nil {
+		t.Errorf("status = %+v, want a dirty clone on main", status)
+	}
+
+	// По тому же пути склонирован alice/tool: состояние чужого клона не показывается
+	status = c.LocalStatus(context.Background(), acme, account)
+	if !status.Occupied || status.Cloned || status.Branch != "" || status.Dirty {
+		t.Errorf("status = %+v, want the path occupied by other remote", status)
+	}
+	if got := status.String(); got != "⚠ path occupied by other remote" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestScanLocal(t *testing.T) {
+	requireGit(t)
+	c := newTestClient(t)
+	c.Settings.CloneTemplate = "{root}/{owner}/{name}"
+	c.Settings.CloneWorkers = 2
+	account := models.Account{Name: "work", Token: "secret"}
+	cloned := newBareRepo(t, "alice", "tool")
+	if result, _ := cloneOne(t, c, cloned, account); !result.Success {
+		t.Fatalf("clone failed: %+v", result)
+	}
+	repos := []models.Repository{cloned}
+	for i := 0; i < 4; i++ {
+		repos = append(repos, models.Repository{Owner: "alice", Name: fmt.Sprintf("missing-%d", i), Host: "github.com"})
+	}
+
+	job := c.ScanLocal(context.Background(), 7, repos, account)
+	statuses := map[string]models.LocalStatus{}
+	for {
+		msg, ok := job.Wait()().(models.LocalStatusMsg)
+		if !ok {
+			break
+		}
+		if msg.Scan != 7 {
+			t.Errorf("Scan = %d, want 7", msg.Scan)
+		}
+		statuses[msg.Repo.Key()] = msg.Status
+	}
+	if len(statuses) != len(repos) {
+		t.Fatalf("got %d statuses, want %d", len(statuses), len(repos))
+	}
+	if !statuses[cloned.Key()].Cloned {
+		t.Errorf("status of %s = %+v, want cloned", cloned.Key(), statuses[cloned.Key()])
+	}
+}
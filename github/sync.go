@@ -0,0 +1,72 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/KharpukhaevV/gitui/models"
+)
+
+// ClonedRepos возвращает репозитории, для которых по пути клонирования уже есть git checkout
+func (c *Client) ClonedRepos(repos []models.Repository, account models.Account) []models.Repository {
+	var cloned []models.Repository
+	for _, repo := range repos {
+		repoDir, err := c.RepoPath(account, repo)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(repoDir, ".git")); err == nil {
+			cloned = append(cloned, repo)
+		}
+	}
+	return cloned
+}
+
+// inspectExisting проверяет занятую директорию клонирования: если в ней склонирован
+// тот же репозиторий, возвращает сообщение с Existing, иначе ошибку
+func (c *Client) inspectExisting(repo models.Repository, account models.Account, repoDir string) models.CloneMsg {
+	if err := checkRemote(repoDir, repo, account); err != nil {
+		return models.CloneMsg{Repo: repo, Success: false, Err: err, Path: repoDir}
+	}
+	return models.CloneMsg{Repo: repo, Success: false, Existing: true, Path: repoDir}
+}
+
+// sync выполняет fetch или fast-forward pull уже склонированного репозитория
+func (c *Client) sync(job *Job, repo models.Repository, account models.Account, action string) models.CloneMsg {
+	repoDir, err := c.RepoPath(account, repo)
+	if err != nil {
+		return models.CloneMsg{Repo: repo, Success: false, Err: err}
+	}
+	if err := checkRemote(repoDir, repo, account); err != nil {
+		return models.CloneMsg{Repo: repo, Success: false, Err: err, Path: repoDir}
+	}
+
+	var cmd *exec.Cmd
+	switch action {
+	case models.ActionFetch:
+		job.progress(models.CloneProgressMsg{Repo: repo, Phase: "Fetching"})
+		cmd = gitCommand(job.ctx, account.Token, "-C", repoDir, "fetch", "--prune", "--progress", "origin")
+	case models.ActionPull:
+		job.progress(models.CloneProgressMsg{Repo: repo, Phase: "Pulling"})
+		cmd = gitCommand(job.ctx, account.Token, "-C", repoDir, "pull", "--ff-only", "--progress")
+	default:
+		return models.CloneMsg{Repo: repo, Success: false, Err: fmt.Errorf("unknown action %q", action), Path: repoDir}
+	}
+
+	if output, err := runWithProgress(job, repo, cmd); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return models.CloneMsg{Repo: repo, Success: false, Canceled: true, Err: context.Canceled, Path: repoDir}
+		}
+		return models.CloneMsg{
+			Repo:    repo,
+			Success: false,
+			Err:     fmt.Errorf("git %s failed: %v, output: %s", action, err, ScrubSecrets(output, account.Token)),
+			Path:    repoDir,
+		}
+	}
+	return models.CloneMsg{Repo: repo, Success: true, Path: repoDir}
+}
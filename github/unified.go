@@ -0,0 +1,147 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/KharpukhaevV/gitui/models"
+)
+
+// LoadAllRepos параллельно загружает репозитории всех аккаунтов и объединяет их
+// в один список (см. MergeRepos). Кэш каждого аккаунта обновляется как при LoadRepos.
+// Ошибки отдельных аккаунтов объединяются в Err, а репозитории остальных
+// аккаунтов все равно возвращаются. Если аккаунт уперся в лимит запросов,
+// RetryAt содержит самое позднее время, после которого можно повторить загрузку.
+//
+// Задача передает ReposLoadedMsg с Partial для каждой новой загруженной страницы:
+// в нем только репозитории, которых еще не было на предыдущих страницах любого аккаунта.
+// Итоговый ReposLoadedMsg содержит объединенный список с полными Accounts.
+func (c *Client) LoadAllRepos(ctx context.Context, request int, accounts []models.Account) *Job {
+	job := newJobContext(ctx)
+	go func() {
+		defer job.close()
+
+		lists := make([][]models.Repository, len(accounts))
+		errs := make([]error, len(accounts))
+		fetched := make([]time.Time, len(accounts))
+		retries := make([]time.Time, len(accounts))
+
+		// Репозитории, уже переданные страницами, чтобы не показывать их дважды
+		var mu sync.Mutex
+		seen := map[string]bool{}
+
+		var wg sync.WaitGroup
+		for i, account := range accounts {
+			if account.Client == nil {
+				errs[i] = fmt.Errorf("%s: GitHub client not initialized", account.Name)
+				continue
+			}
+			wg.Add(1)
+			go func(i int, account models.Account) {
+				defer wg.Done()
+				onPage := func(repos []models.Repository) {
+					mu.Lock()
+					var batch []models.Repository
+					for _, repo := range repos {
+						if seen[repo.Key()] {
+							continue
+						}
+						seen[repo.Key()] = true
+						repo.Accounts = []string{account.Name}
+						batch = append(batch, repo)
+					}
+					mu.Unlock()
+					if len(batch) > 0 {
+						job.deliver(models.ReposLoadedMsg{Request: request, Repos: batch, Partial: true})
+					}
+				}
+				cache, _, err := c.fetchRepos(job.ctx, account, models.Owner{}, c.readCache(account, models.Owner{}), onPage)
+				if err != nil {
+					if retryAt, ok := c.rateLimitRetry(account, err); ok {
+						retries[i] = retryAt
+					}
+					errs[i] = fmt.Errorf("%s: %v", account.Name, err)
+					return
+				}
+				c.writeCache(account, models.Owner{}, cache)
+				lists[i] = cache.repos()
+				fetched[i] = cache.FetchedAt
+			}(i, account)
+		}
+		wg.Wait()
+
+		job.deliver(models.ReposLoadedMsg{
+			Request:   request,
+			Repos:     MergeRepos(accounts, lists),
+			FetchedAt: oldest(fetched),
+			RetryAt:   latest(retries),
+			Err:       errors.Join(errs...),
+		})
+	}()
+	return job
+}
+
+// CachedAllRepos возвращает объединенный список репозиториев всех аккаунтов из кэша
+// на диске и время самой старой загрузки. Аккаунты без кэша пропускаются.
+func (c *Client) CachedAllRepos(accounts []models.Account) ([]models.Repository, time.Time, bool) {
+	lists := make([][]models.Repository, len(accounts))
+	fetched := make([]time.Time, len(accounts))
+	found := false
+	for i, account := range accounts {
+		if repos, fetchedAt, ok := c.CachedRepos(account, models.Owner{}); ok {
+			lists[i] = repos
+			fetched[i] = fetchedAt
+			found = true
+		}
+	}
+	if !found {
+		return nil, time.Time{}, false
+	}
+	return MergeRepos(accounts, lists), oldest(fetched), true
+}
+
+// MergeRepos объединяет списки репозиториев аккаунтов (lists[i] принадлежит accounts[i]).
+// Репозиторий, доступный нескольким аккаунтам, попадает в список один раз,
+// а в Accounts перечисляются все аккаунты с доступом в порядке accounts.
+func MergeRepos(accounts []models.Account, lists [][]models.Repository) []models.Repository {
+	var merged []models.Repository
+	index := map[string]int{}
+	for i, repos := range lists {
+		for _, repo := range repos {
+			key := repo.Key()
+			if j, ok := index[key]; ok {
+				merged[j].Accounts = append(merged[j].Accounts, accounts[i].Name)
+				continue
+			}
+			repo.Accounts = []string{accounts[i].Name}
+			index[key] = len(merged)
+			merged = append(merged, repo)
+		}
+	}
+	return merged
+}
+
+// oldest возвращает самое раннее из известных времен
+func oldest(times []time.Time) time.Time {
+	var result time.Time
+	for _, t := range times {
+		if !t.IsZero() && (result.IsZero() || t.Before(result)) {
+			result = t
+		}
+	}
+	return result
+}
+
+// latest возвращает самое позднее из известных времен
+func latest(times []time.Time) time.Time {
+	var result time.Time
+	for _, t := range times {
+		if t.After(result) {
+			result = t
+		}
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KharpukhaevV/gitui/github/githubtest"
+	"github.com/KharpukhaevV/gitui/models"
+)
+
+// unifiedAccount возвращает аккаунт с указанным именем, подключенный к фейковому API
+func unifiedAccount(t *testing.T, fake *githubtest.Server, name string) models.Account {
+	account := fake.Account(t)
+	account.Name = name
+	return account
+}
+
+func TestLoadAllRepos(t *testing.T) {
+	// Оба аккаунта видят одни и те же репозитории
+	fake := githubtest.NewServer(t,
+		[]map[string]interface{}{githubtest.Repo("acme", "api"), githubtest.Repo("octocat", "dotfiles")},
+		[]map[string]interface{}{githubtest.Repo("acme", "web")})
+	c := newTestClient(t)
+	accounts := []models.Account{unifiedAccount(t, fake, "work"), unifiedAccount(t, fake, "personal")}
+
+	partials, final := drainRepos(t, c.LoadAllRepos(context.Background(), 3, accounts))
+	if final.Err != nil || !final.RetryAt.IsZero() {
+		t.Fatalf("LoadAllRepos: %v, RetryAt %v", final.Err, final.RetryAt)
+	}
+
+	// Страницы приходят по мере загрузки, каждый репозиторий один раз
+	streamed := map[string]int{}
+	for _, page := range partials {
+		if page.Request != 3 {
+			t.Errorf("page Request = %d, want 3", page.Request)
+		}
+		for _, repo := range page.Repos {
+			streamed[repo.Title()]++
+			if len(repo.Accounts) != 1 {
+				t.Errorf("page repo %s accounts = %v, want one", repo.Title(), repo.Accounts)
+			}
+		}
+	}
+	if len(streamed) != 3 {
+		t.Errorf("streamed %v, want 3 repositories", streamed)
+	}
+	for name, n := range streamed {
+		if n != 1 {
+			t.Errorf("%s streamed %d times", name, n)
+		}
+	}
+
+	if got := strings.Join(repoNames(final.Repos), ","); got != "acme/api,octocat/dotfiles,acme/web" {
+		t.Errorf("repos = %s", got)
+	}
+	for _, repo := range final.Repos {
+		if got := strings.Join(repo.Accounts, ","); got != "work,personal" {
+			t.Errorf("%s accounts = %s, want work,personal", repo.Title(), got)
+		}
+	}
+}
+
+func TestLoadAllReposRateLimit(t *testing.T) {
+	work := githubtest.NewServer(t, []map[string]interface{}{githubtest.Repo("acme", "api")})
+	limited := githubtest.NewServer(t, []map[string]interface{}{githubtest.Repo("octocat", "blog")})
+	secondary := githubtest.NewServer(t, []map[string]interface{}{githubtest.Repo("octocat", "notes")})
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	limited.Fail(http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(reset.Unix(), 10)})
+	secondary.Fail(http.StatusTooManyRequests, map[string]string{"Retry-After": "30"})
+	c := newTestClient(t)
+	accounts := []models.Account{
+		unifiedAccount(t, work, "work"),
+		unifiedAccount(t, limited, "personal"),
+		unifiedAccount(t, secondary, "oss"),
+	}
+
+	_, final := drainRepos(t, c.LoadAllRepos(context.Background(), 1, accounts))
+	if final.Err == nil {
+		t.Fatal("expected an error")
+	}
+	// Повторять загрузку имеет смысл только после сброса самого позднего лимита
+	if !final.RetryAt.Equal(reset) {
+		t.Errorf("RetryAt = %v, want %v", final.RetryAt, reset)
+	}
+	if got := strings.Join(repoNames(final.Repos), ","); got != "acme/api" {
+		t.Errorf("repos = %s, want acme/api", got)
+	}
+}
@@ -4,11 +4,17 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/KharpukhaevV/gitui/cli"
 	"github.com/KharpukhaevV/gitui/ui"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
+	// С аргументами выполняем неинтерактивную команду, без них запускаем TUI
+	if len(os.Args) > 1 {
+		os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr))
+	}
+
 	model := ui.NewAppModel()
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
@@ -1,19 +1,147 @@
 package models
 
 import (
+	"context"
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/google/go-github/github"
+	"golang.org/x/oauth2"
 )
 
 // Account представляет аккаунт GitHub
 type Account struct {
-	Name    string    `json:"name"`
-	Token   string    `json:"token"`
-	Created time.Time `json:"created"`
-	Private bool      `json:"private"`
-	Client  *github.Client
+	Name          string         `json:"name"`
+	Token         string         `json:"token,omitempty"`
+	SealedToken   string         `json:"sealed_token,omitempty"`
+	TokenBackend  string         `json:"token_backend,omitempty"`
+	TokenRef      string         `json:"token_ref,omitempty"`
+	Login         string         `json:"login,omitempty"`
+	AvatarURL     string         `json:"avatar_url,omitempty"`
+	Scopes        []string       `json:"scopes,omitempty"`
+	APIURL        string         `json:"api_url,omitempty"`
+	Host          string         `json:"host,omitempty"`
+	CloneProtocol string         `json:"clone_protocol,omitempty"`
+	SSHKey        string         `json:"ssh_key,omitempty"`
+	SSHHost       string         `json:"ssh_host,omitempty"`
+	GitName       string         `json:"git_name,omitempty"`
+	GitEmail      string         `json:"git_email,omitempty"`
+	SigningKey    string         `json:"signing_key,omitempty"`
+	CloneRoot     string         `json:"clone_root,omitempty"`
+	SortBy        string         `json:"sort_by,omitempty"`
+	SortDesc      bool           `json:"sort_desc,omitempty"`
+	Created       time.Time      `json:"created"`
+	Private       bool           `json:"private"`
+	Client        *github.Client `json:"-"`
+}
+
+// DefaultHost хост github.com
+const DefaultHost = "github.com"
+
+// Протоколы клонирования
+const (
+	ProtocolHTTPS = "https"
+	ProtocolSSH   = "ssh"
+)
+
+// Protocol возвращает протокол клонирования аккаунта по умолчанию
+func (a Account) Protocol() string {
+	if a.CloneProtocol == ProtocolSSH {
+		return ProtocolSSH
+	}
+	return ProtocolHTTPS
+}
+
+// Connect создает клиент GitHub для токена аккаунта.
+// Для GitHub Enterprise Server используется API по адресу APIURL.
+func (a *Account) Connect() error {
+	a.Client = nil
+	if a.Token == "" {
+		return nil
+	}
+	ts := oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: a.Token},
+	)
+	tc := oauth2.NewClient(context.Background(), ts)
+
+	if a.APIURL == "" {
+		a.Client = github.NewClient(tc)
+		return nil
+	}
+
+	baseURL, uploadURL, err := EnterpriseURLs(a.APIURL)
+	if err != nil {
+		return err
+	}
+	client, err := github.NewEnterpriseClient(baseURL, uploadURL, tc)
+	if err != nil {
+		return err
+	}
+	a.Client = client
+	return nil
+}
+
+// HasIdentity сообщает, задана ли для аккаунта git identity
+func (a Account) HasIdentity() bool {
+	return a.GitName != "" || a.GitEmail != "" || a.SigningKey != ""
+}
+
+// WebHost возвращает хост, с которого клонируются репозитории аккаунта
+func (a Account) WebHost() string {
+	if a.Host != "" {
+		return a.Host
+	}
+	if a.APIURL != "" {
+		if u, err := url.Parse(a.APIURL); err == nil && u.Host != "" {
+			return u.Host
+		}
+	}
+	return DefaultHost
+}
+
+// EnterpriseURLs возвращает адреса API и загрузок GitHub Enterprise Server.
+// Для адреса без пути (например, https://ghe.example.com) добавляется /api/v3/.
+func EnterpriseURLs(apiURL string) (string, string, error) {
+	u, err := url.Parse(strings.TrimSpace(apiURL))
+	if err != nil {
+		return "", "", err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", "", fmt.Errorf("invalid API URL %q: scheme and host are required", apiURL)
+	}
+
+	if strings.Trim(u.Path, "/") == "" {
+		u.Path = "/api/v3/"
+	} else if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	baseURL := u.String()
+
+	u.Path = strings.Replace(u.Path, "/api/v3/", "/api/uploads/", 1)
+	return baseURL, u.String(), nil
+}
+
+// HasScope проверяет, выдана ли токену указанная область доступа
+func (a Account) HasScope(scope string) bool {
+	for _, s := range a.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseScopes разбирает значение заголовка X-OAuth-Scopes
+func ParseScopes(header string) []string {
+	scopes := []string{}
+	for _, s := range strings.Split(header, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
 }
 
 // Title возвращает название аккаунта для отображения в списке
@@ -27,7 +155,14 @@ func (a Account) Description() string {
 	if a.Private {
 		private = "Private"
 	}
-	return fmt.Sprintf("Created: %s • %s", a.Created.Format("2006-01-02"), private)
+	desc := fmt.Sprintf("Created: %s • %s", a.Created.Format("2006-01-02"), private)
+	if host := a.WebHost(); host != DefaultHost {
+		desc = host + " • " + desc
+	}
+	if a.Login != "" {
+		desc = "@" + a.Login + " • " + desc
+	}
+	return desc
 }
 
 // FilterValue возвращает значение для фильтрации
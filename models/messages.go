@@ -1,15 +1,105 @@
 package models
 
-// ReposLoadedMsg сообщение о загрузке репозиториев
+import "time"
+
+// ReposLoadedMsg сообщение о загрузке репозиториев владельца.
+// NotModified означает, что список не изменился с прошлой загрузки.
+// RetryAt задан, если загрузка отклонена из-за лимита запросов и ее можно повторить позже.
+// Request и Account позволяют отбросить ответ на устаревший запрос.
+// Partial означает очередную загруженную страницу: Repos содержит только ее репозитории,
+// а полный список придет в последнем сообщении.
 type ReposLoadedMsg struct {
-	Repos []Repository
-	Err   error
+	Request     int
+	Account     string
+	Owner       Owner
+	Repos       []Repository
+	FetchedAt   time.Time
+	NotModified bool
+	Partial     bool
+	RetryAt     time.Time
+	Err         error
+}
+
+// RateLimitMsg сообщение о текущих лимитах запросов аккаунта
+type RateLimitMsg struct {
+	Account string
+	Core    RateLimit
+	Search  RateLimit
+	Err     error
+}
+
+// OwnersLoadedMsg сообщение о загрузке пользователя и его организаций
+type OwnersLoadedMsg struct {
+	Request int
+	Owners  []Owner
+	Err     error
 }
 
-// CloneMsg сообщение о клонировании репозитория
+// Действия очереди клонирования
+const (
+	ActionClone = "clone"
+	ActionFetch = "fetch"
+	ActionPull  = "pull"
+)
+
+// CloneMsg сообщение о клонировании или синхронизации репозитория.
+// Err при Success == true означает, что клонирование прошло, но не удалось применить git identity.
+// Existing означает, что репозиторий уже склонирован в Path и клонирование пропущено.
 type CloneMsg struct {
+	Account  string
+	Repo     Repository
+	Action   string
+	Success  bool
+	Canceled bool
+	Existing bool
+	Err      error
+	Path     string
+}
+
+// CloneQueueDoneMsg сообщение о завершении очереди клонирования
+type CloneQueueDoneMsg struct {
+	Account  string
+	Cloned   int
+	Failed   int
+	Canceled int
+	Existing int
+}
+
+// CloneProgressMsg сообщение о ходе клонирования репозитория
+type CloneProgressMsg struct {
+	Repo    Repository
+	Phase   string
+	Percent float64
+}
+
+// LocalStatusMsg сообщение о состоянии локального клона репозитория.
+// Scan номер сканирования: результаты устаревших сканирований игнорируются.
+type LocalStatusMsg struct {
+	Scan   int
+	Repo   Repository
+	Status LocalStatus
+}
+
+// AccountValidatedMsg сообщение о проверке токена нового аккаунта
+type AccountValidatedMsg struct {
+	Request int
+	Account Account
+	Err     error
+}
+
+// IdentityAppliedMsg сообщение о применении git identity к склонированным репозиториям
+// Skipped репозитории, по пути которых склонирован другой remote.
+type IdentityAppliedMsg struct {
+	Applied int
+	Skipped int
+	Err     error
+}
+
+// ReadmeLoadedMsg сообщение о загрузке README репозитория.
+// Пустой Content без ошибки означает, что README в репозитории нет.
+type ReadmeLoadedMsg struct {
+	Request int
 	Repo    Repository
-	Success bool
+	Content string
 	Err     error
-	Path    string
 }
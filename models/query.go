@@ -0,0 +1,187 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Facet условие фильтра репозиториев вида ключ:значение, например lang:go или stars:>10.
+// Negate инвертирует условие (-lang:go).
+type Facet struct {
+	Key    string
+	Op     string
+	Value  string
+	Negate bool
+}
+
+// String возвращает условие в синтаксисе запроса
+func (f Facet) String() string {
+	s := f.Key + ":" + f.Op + f.Value
+	if f.Negate {
+		return "-" + s
+	}
+	return s
+}
+
+// RepoQuery разобранный запрос фильтра: условия по полям и текст для нечеткого поиска
+type RepoQuery struct {
+	Facets []Facet
+	Text   string
+}
+
+// textFacets ключи условий, сравниваемых со строковым полем репозитория
+var textFacets = map[string]bool{"lang": true, "owner": true, "topic": true, "license": true}
+
+// numberFacets ключи условий, сравниваемых с числовым полем репозитория
+var numberFacets = map[string]bool{"stars": true, "forks": true, "size": true, "issues": true}
+
+// boolFacets ключи условий с логическим значением
+var boolFacets = map[string]bool{"archived": true, "fork": true, "private": true}
+
+// isValues значения условия is:
+var isValues = map[string]bool{"private": true, "public": true, "fork": true, "source": true, "archived": true}
+
+// ParseQuery разбирает запрос фильтра. Слова с неизвестным ключом считаются текстом,
+// а неверное значение известного ключа — ошибкой.
+func ParseQuery(query string) (RepoQuery, error) {
+	var q RepoQuery
+	var text []string
+	for _, word := range strings.Fields(query) {
+		facet, ok, err := parseFacet(word)
+		if err != nil {
+			return q, err
+		}
+		if !ok {
+			text = append(text, word)
+			continue
+		}
+		q.Facets = append(q.Facets, facet)
+	}
+	q.Text = strings.Join(text, " ")
+	return q, nil
+}
+
+// parseFacet разбирает слово запроса как условие. Второе значение сообщает,
+// что слово является условием.
+func parseFacet(word string) (Facet, bool, error) {
+	var facet Facet
+	if strings.HasPrefix(word, "-") {
+		facet.Negate = true
+		word = word[1:]
+	}
+	key, value, ok := strings.Cut(word, ":")
+	if !ok {
+		return facet, false, nil
+	}
+	facet.Key = strings.ToLower(key)
+	facet.Value = strings.ToLower(value)
+
+	switch {
+	case facet.Key == "is":
+		if !isValues[facet.Value] {
+			return facet, true, fmt.Errorf("unknown value %q for is:, expected private, public, fork, source or archived", value)
+		}
+	case textFacets[facet.Key]:
+		if facet.Value == "" {
+			return facet, true, fmt.Errorf("empty value for %s:", key)
+		}
+	case boolFacets[facet.Key]:
+		if _, err := strconv.ParseBool(facet.Value); err != nil {
+			return facet, true, fmt.Errorf("expected true or false for %s:", key)
+		}
+	case numberFacets[facet.Key]:
+		for _, op := range []string{">=", "<=", ">", "<"} {
+			if strings.HasPrefix(facet.Value, op) {
+				facet.Op = op
+				facet.Value = strings.TrimPrefix(facet.Value, op)
+				break
+			}
+		}
+		if _, err := strconv.Atoi(facet.Value); err != nil {
+			return facet, true, fmt.Errorf("expected a number for %s:, e.g. %s:>10", key, key)
+		}
+	default:
+		return facet, false, nil
+	}
+	return facet, true, nil
+}
+
+// Matches проверяет, что репозиторий удовлетворяет всем условиям запроса.
+// Текст запроса не проверяется: он используется для нечеткого поиска.
+func (q RepoQuery) Matches(repo Repository) bool {
+	for _, facet := range q.Facets {
+		if facet.matches(repo) == facet.Negate {
+			return false
+		}
+	}
+	return true
+}
+
+// matches проверяет условие без учета Negate
+func (f Facet) matches(repo Repository) bool {
+	switch f.Key {
+	case "is":
+		switch f.Value {
+		case "private":
+			return repo.IsPrivate
+		case "public":
+			return !repo.IsPrivate
+		case "fork":
+			return repo.IsFork
+		case "source":
+			return !repo.IsFork
+		case "archived":
+			return repo.Archived
+		}
+	case "lang":
+		return strings.EqualFold(repo.Language, f.Value)
+	case "owner":
+		return strings.EqualFold(repo.Owner, f.Value)
+	case "license":
+		return strings.EqualFold(repo.License, f.Value)
+	case "topic":
+		for _, topic := range repo.Topics {
+			if strings.EqualFold(topic, f.Value) {
+				return true
+			}
+		}
+	case "archived":
+		return repo.Archived == f.boolValue()
+	case "fork":
+		return repo.IsFork == f.boolValue()
+	case "private":
+		return repo.IsPrivate == f.boolValue()
+	case "stars":
+		return f.compare(repo.Stars)
+	case "forks":
+		return f.compare(repo.Forks)
+	case "size":
+		return f.compare(repo.Size)
+	case "issues":
+		return f.compare(repo.OpenIssues)
+	}
+	return false
+}
+
+// boolValue возвращает логическое значение условия
+func (f Facet) boolValue() bool {
+	value, _ := strconv.ParseBool(f.Value)
+	return value
+}
+
+// compare сравнивает число с числовым условием
+func (f Facet) compare(n int) bool {
+	value, _ := strconv.Atoi(f.Value)
+	switch f.Op {
+	case ">":
+		return n > value
+	case ">=":
+		return n >= value
+	case "<":
+		return n < value
+	case "<=":
+		return n <= value
+	}
+	return n == value
+}
@@ -0,0 +1,195 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		query  string
+		facets []string
+		text   string
+		err    string
+	}{
+		{query: "", text: ""},
+		{query: "  api   client ", text: "api client"},
+		{query: "lang:go", facets: []string{"lang:go"}},
+		{query: "Lang:Go", facets: []string{"lang:go"}},
+		{query: "-lang:go", facets: []string{"-lang:go"}},
+		{query: "lang:go api -topic:cli", facets: []string{"lang:go", "-topic:cli"}, text: "api"},
+		{query: "stars:10", facets: []string{"stars:10"}},
+		{query: "stars:>10", facets: []string{"stars:>10"}},
+		{query: "stars:>=10", facets: []string{"stars:>=10"}},
+		{query: "forks:<3 size:<=100 issues:0", facets: []string{"forks:<3", "size:<=100", "issues:0"}},
+		{query: "stars:>", err: "expected a number for stars:"},
+		{query: "stars:many", err: "expected a number for stars:"},
+		{query: "stars:=>10", err: "expected a number for stars:"},
+		{query: "is:private", facets: []string{"is:private"}},
+		{query: "-is:fork is:ARCHIVED", facets: []string{"-is:fork", "is:archived"}},
+		{query: "is:", err: `unknown value "" for is:`},
+		{query: "is:secret", err: `unknown value "secret" for is:`},
+		{query: "archived:true fork:0", facets: []string{"archived:true", "fork:0"}},
+		{query: "private:maybe", err: "expected true or false for private:"},
+		{query: "lang:", err: "empty value for lang:"},
+		{query: "-owner:", err: "empty value for owner:"},
+		// Неизвестные ключи и слова без двоеточия считаются текстом
+		{query: "foo:bar", text: "foo:bar"},
+		{query: "-foo:bar -draft", text: "-foo:bar -draft"},
+		{query: "https://github.com", text: "https://github.com"},
+		{query: "-", text: "-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := ParseQuery(tt.query)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("ParseQuery(%q) error = %v, want %q", tt.query, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseQuery(%q) error = %v", tt.query, err)
+			}
+
+			var facets []string
+			for _, facet := range q.Facets {
+				facets = append(facets, facet.String())
+			}
+			if !reflect.DeepEqual(facets, tt.facets) {
+				t.Errorf("facets = %q, want %q", facets, tt.facets)
+			}
+			if q.Text != tt.text {
+				t.Errorf("text = %q, want %q", q.Text, tt.text)
+			}
+		})
+	}
+}
+
+func TestQueryMatches(t *testing.T) {
+	repo := Repository{
+		Owner: "Acme", Name: "api", Language: "Go", License: "MIT", Topics: []string{"CLI", "github"},
+		Stars: 10, Forks: 2, Size: 512, OpenIssues: 0, IsPrivate: true,
+	}
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", true},
+		{"unrelated text", true},
+		{"lang:go", true},
+		{"lang:rust", false},
+		{"-lang:go", false},
+		{"-lang:rust", true},
+		{"owner:acme", true},
+		{"license:mit", true},
+		{"topic:cli", true},
+		{"topic:web", false},
+		{"-topic:web", true},
+		{"stars:10", true},
+		{"stars:>10", false},
+		{"stars:>=10", true},
+		{"stars:<10", false},
+		{"stars:<=10", true},
+		{"forks:>1 size:<1000 issues:0", true},
+		{"is:private", true},
+		{"is:public", false},
+		{"is:source", true},
+		{"is:fork", false},
+		{"-is:archived", true},
+		{"private:true fork:false archived:false", true},
+		{"private:false", false},
+		// Все условия должны выполняться
+		{"lang:go stars:>100", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := q.Matches(repo); got != tt.want {
+				t.Errorf("Matches(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+// repoTitles возвращает названия репозиториев owner/name
+func repoTitles(repos []Repository) []string {
+	titles := make([]string, len(repos))
+	for i, repo := range repos {
+		titles[i] = repo.Title()
+	}
+	return titles
+}
+
+func TestSortRepos(t *testing.T) {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	repos := []Repository{
+		{Owner: "zed", Name: "beta", Stars: 5, UpdatedAt: day},
+		{Owner: "acme", Name: "web", Stars: 5, UpdatedAt: day.AddDate(0, 0, 2)},
+		{Owner: "Acme", Name: "api", Stars: 1, UpdatedAt: day},
+		{Owner: "bob", Name: "alpha", Stars: 9, UpdatedAt: day.AddDate(0, 0, 1)},
+	}
+
+	tests := []struct {
+		key  string
+		desc bool
+		want []string
+	}{
+		{SortDefault, false, []string{"zed/beta", "acme/web", "Acme/api", "bob/alpha"}},
+		{SortDefault, true, []string{"bob/alpha", "Acme/api", "acme/web", "zed/beta"}},
+		{SortName, false, []string{"bob/alpha", "Acme/api", "zed/beta", "acme/web"}},
+		{SortStars, false, []string{"Acme/api", "acme/web", "zed/beta", "bob/alpha"}},
+		// Равные значения упорядочиваются по владельцу и имени и при обратной сортировке
+		{SortStars, true, []string{"bob/alpha", "acme/web", "zed/beta", "Acme/api"}},
+		{SortUpdated, false, []string{"Acme/api", "zed/beta", "bob/alpha", "acme/web"}},
+		{SortUpdated, true, []string{"acme/web", "bob/alpha", "Acme/api", "zed/beta"}},
+		{SortOwner, false, []string{"Acme/api", "acme/web", "bob/alpha", "zed/beta"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(SortLabel(tt.key, tt.desc), func(t *testing.T) {
+			if got := repoTitles(SortRepos(repos, tt.key, tt.desc)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SortRepos = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := repoTitles(repos); got[0] != "zed/beta" {
+		t.Errorf("SortRepos modified its input: %q", got)
+	}
+}
+
+func TestSortReposStable(t *testing.T) {
+	// Порядок при равенстве не зависит от исходного порядка списка
+	repos := []Repository{
+		{Owner: "b", Name: "two", Forks: 1},
+		{Owner: "a", Name: "one", Forks: 1},
+		{Owner: "a", Name: "three", Forks: 1},
+	}
+	reversed := []Repository{repos[2], repos[1], repos[0]}
+	want := []string{"a/one", "a/three", "b/two"}
+	for _, list := range [][]Repository{repos, reversed} {
+		if got := repoTitles(SortRepos(list, SortForks, false)); !reflect.DeepEqual(got, want) {
+			t.Errorf("SortRepos(%q) = %q, want %q", repoTitles(list), got, want)
+		}
+	}
+
+	// Полностью равные репозитории (один репозиторий с разных хостов) сохраняют исходный порядок
+	same := []Repository{
+		{Owner: "acme", Name: "api", Host: "github.com"},
+		{Owner: "acme", Name: "api", Host: "ghe.example.com"},
+	}
+	for i := 0; i < 10; i++ {
+		sorted := SortRepos(same, SortName, i%2 == 1)
+		if sorted[0].Host != "github.com" || sorted[1].Host != "ghe.example.com" {
+			t.Fatalf("equal repositories reordered: %+v", sorted)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// RateLimit состояние лимита запросов к API GitHub
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// Known сообщает, получены ли данные о лимите
+func (r RateLimit) Known() bool {
+	return r.Limit > 0
+}
+
+// Low сообщает, что осталось меньше 10% запросов
+func (r RateLimit) Low() bool {
+	return r.Known() && r.Remaining*10 < r.Limit
+}
+
+// String возвращает остаток лимита и время его сброса
+func (r RateLimit) String() string {
+	if !r.Known() {
+		return "unknown"
+	}
+	return fmt.Sprintf("%d/%d, resets at %s", r.Remaining, r.Limit, r.Reset.Local().Format("15:04"))
+}
@@ -7,16 +7,32 @@ import (
 
 // Repository представляет репозиторий GitHub
 type Repository struct {
-	Name      string
-	Desc      string
-	Stars     int
-	Forks     int
-	Language  string
-	UpdatedAt time.Time
-	IsPrivate bool
-	SSHURL    string
-	CloneURL  string
-	Owner     string
+	Name          string
+	Desc          string
+	Stars         int
+	Forks         int
+	Language      string
+	UpdatedAt     time.Time
+	PushedAt      time.Time
+	IsPrivate     bool
+	IsFork        bool
+	Archived      bool
+	SSHURL        string
+	CloneURL      string
+	Owner         string
+	Host          string
+	Topics        []string
+	DefaultBranch string
+	License       string
+	Size          int // в килобайтах
+	OpenIssues    int
+	Homepage      string
+	Accounts      []string // аккаунты с доступом к репозиторию, только в общем списке всех аккаунтов
+}
+
+// Key возвращает уникальный ключ репозитория (хост/владелец/имя)
+func (r Repository) Key() string {
+	return fmt.Sprintf("%s/%s/%s", r.Host, r.Owner, r.Name)
 }
 
 // Title возвращает название репозитория для отображения в списке
@@ -38,7 +54,97 @@ func (r Repository) Description() string {
 		desc, private, r.Stars, r.Forks, r.Language, r.UpdatedAt.Format("2006-01-02"))
 }
 
-// FilterValue возвращает значение для фильтрации
+// WebURL возвращает адрес страницы репозитория
+func (r Repository) WebURL() string {
+	return fmt.Sprintf("https://%s/%s/%s", r.Host, r.Owner, r.Name)
+}
+
+// AccountFor выбирает среди accounts аккаунт для работы с репозиторием общего списка:
+// первый из Accounts с клиентом GitHub, а если клиента нет ни у одного — первый найденный
+func (r Repository) AccountFor(accounts []Account) (Account, bool) {
+	var fallback *Account
+	for _, name := range r.Accounts {
+		for i := range accounts {
+			if accounts[i].Name != name {
+				continue
+			}
+			if accounts[i].Client != nil {
+				return accounts[i], true
+			}
+			if fallback == nil {
+				fallback = &accounts[i]
+			}
+		}
+	}
+	if fallback != nil {
+		return *fallback, true
+	}
+	return Account{}, false
+}
+
+// FilterValue возвращает значение для нечеткого поиска: владелец, имя и описание
 func (r Repository) FilterValue() string {
-	return r.Name
+	return r.Title() + " " + r.Desc
+}
+
+// Owner владелец репозиториев: пользователь или организация.
+// Пустой Login означает все репозитории, доступные пользователю.
+type Owner struct {
+	Login string
+	Org   bool
+}
+
+// String возвращает название владельца для отображения
+func (o Owner) String() string {
+	switch {
+	case o.Login == "":
+		return "all repositories"
+	case o.Org:
+		return o.Login + " (organization)"
+	}
+	return o.Login
+}
+
+// LocalStatus состояние локального клона репозитория.
+// Occupied означает, что по пути клона склонирован другой репозиторий.
+type LocalStatus struct {
+	Cloned   bool
+	Occupied bool
+	Branch   string
+	Dirty    bool
+	Upstream bool
+	Ahead    int
+	Behind   int
+	Err      error
+}
+
+// String возвращает краткое описание состояния клона для списка
+func (s LocalStatus) String() string {
+	if s.Occupied {
+		return "⚠ path occupied by other remote"
+	}
+	if !s.Cloned {
+		return "not cloned"
+	}
+	if s.Err != nil {
+		return "📁 cloned (status unavailable)"
+	}
+
+	status := "📁 " + s.Branch
+	if s.Dirty {
+		status += " ✎"
+	}
+	if !s.Upstream {
+		return status + " (no upstream)"
+	}
+	if s.Ahead > 0 {
+		status += fmt.Sprintf(" ↑%d", s.Ahead)
+	}
+	if s.Behind > 0 {
+		status += fmt.Sprintf(" ↓%d", s.Behind)
+	}
+	if s.Ahead == 0 && s.Behind == 0 {
+		status += " ✓"
+	}
+	return status
 }
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/KharpukhaevV/gitui/utils"
+)
+
+// Значения настроек по умолчанию
+const (
+	// DefaultCloneRoot корневая директория для клонирования
+	DefaultCloneRoot = "~/develop"
+	// DefaultCloneTemplate шаблон пути клонируемого репозитория
+	DefaultCloneTemplate = "{root}/{owner}/{name}"
+	// LegacyCloneTemplate прежний шаблон по умолчанию, в котором одноименные
+	// репозитории разных владельцев попадали в одну директорию
+	LegacyCloneTemplate = "{root}/{name}"
+	// DefaultCloneWorkers количество параллельных клонирований
+	DefaultCloneWorkers = 4
+)
+
+// templatePlaceholders подстановки, доступные в шаблоне пути клонирования
+var templatePlaceholders = map[string]bool{"root": true, "host": true, "owner": true, "name": true, "account": true}
+
+// Settings общие настройки приложения
+type Settings struct {
+	CloneRoot     string `json:"clone_root,omitempty"`
+	CloneTemplate string `json:"clone_template,omitempty"`
+	CloneWorkers  int    `json:"clone_workers,omitempty"`
+}
+
+// Workers возвращает количество параллельных клонирований
+func (s Settings) Workers() int {
+	if s.CloneWorkers <= 0 {
+		return DefaultCloneWorkers
+	}
+	return s.CloneWorkers
+}
+
+// RootFor возвращает корневую директорию клонирования для аккаунта.
+// Корень аккаунта имеет приоритет над общим.
+func (s Settings) RootFor(account Account) string {
+	root := s.CloneRoot
+	if account.CloneRoot != "" {
+		root = account.CloneRoot
+	}
+	if root == "" {
+		root = DefaultCloneRoot
+	}
+	return filepath.Clean(utils.ExpandHome(root))
+}
+
+// RepoPath возвращает путь, куда клонируется репозиторий.
+// В шаблоне доступны {root}, {host}, {owner}, {name} и {account}.
+func (s Settings) RepoPath(account Account, repo Repository) (string, error) {
+	template := s.CloneTemplate
+	if template == "" {
+		template = DefaultCloneTemplate
+	}
+	return s.expand(template, account, repo)
+}
+
+// LegacyRepoPath возвращает путь по прежнему шаблону по умолчанию. Второе значение
+// false, если шаблон задан в настройках и прежний путь не используется.
+func (s Settings) LegacyRepoPath(account Account, repo Repository) (string, bool) {
+	if s.CloneTemplate != "" {
+		return "", false
+	}
+	path, err := s.expand(LegacyCloneTemplate, account, repo)
+	return path, err == nil
+}
+
+// expand подставляет значения в шаблон пути клонирования
+func (s Settings) expand(template string, account Account, repo Repository) (string, error) {
+	if err := checkTemplate(template); err != nil {
+		return "", err
+	}
+
+	host := repo.Host
+	if host == "" {
+		host = account.WebHost()
+	}
+
+	root := s.RootFor(account)
+	path := strings.NewReplacer(
+		"{root}", root,
+		"{host}", host,
+		"{owner}", repo.Owner,
+		"{name}", repo.Name,
+		"{account}", account.Name,
+	).Replace(template)
+
+	path = utils.ExpandHome(path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	return filepath.Clean(path), nil
+}
+
+// checkTemplate проверяет, что в шаблоне используются только известные подстановки.
+// Проверяется сам шаблон, а не результат: фигурные скобки в имени репозитория
+// или корневой директории ошибкой не считаются.
+func checkTemplate(template string) error {
+	rest := template
+	for {
+		start := strings.IndexAny(rest, "{}")
+		if start < 0 {
+			return nil
+		}
+		if rest[start] == '}' {
+			return fmt.Errorf("unexpected } in clone template %q", template)
+		}
+		end := strings.Index(rest[start:], "}")
+		if end < 0 {
+			return fmt.Errorf("unclosed placeholder in clone template %q", template)
+		}
+		name := rest[start+1 : start+end]
+		if !templatePlaceholders[name] {
+			return fmt.Errorf("unknown placeholder {%s} in clone template %q", name, template)
+		}
+		rest = rest[start+end+1:]
+	}
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRepoPath(t *testing.T) {
+	account := Account{Name: "work"}
+	repo := Repository{Owner: "acme", Name: "api", Host: "github.com"}
+	tests := []struct {
+		template string
+		root     string
+		want     string
+		err      string
+	}{
+		{template: "", root: "/src", want: "/src/acme/api"},
+		{template: "{root}/{host}/{owner}/{name}", root: "/src", want: "/src/github.com/acme/api"},
+		{template: "{account}/{name}", root: "/src", want: "/src/work/api"},
+		{template: "/opt/{owner}-{name}", root: "/src", want: "/opt/acme-api"},
+		// Фигурные скобки в подставленных значениях не считаются подстановками
+		{template: "{root}/{name}", root: "/src/{tmp}", want: "/src/{tmp}/api"},
+		{template: "{root}/{repo}", root: "/src", err: "unknown placeholder {repo}"},
+		{template: "{root}/{}", root: "/src", err: "unknown placeholder {}"},
+		{template: "{root}/{name", root: "/src", err: "unclosed placeholder"},
+		{template: "{root}/name}", root: "/src", err: "unexpected }"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			settings := Settings{CloneRoot: tt.root, CloneTemplate: tt.template}
+			path, err := settings.RepoPath(account, repo)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("RepoPath error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if path != tt.want {
+				t.Errorf("RepoPath = %q, want %q", path, tt.want)
+			}
+		})
+	}
+}
+
+func TestLegacyRepoPath(t *testing.T) {
+	repo := Repository{Owner: "acme", Name: "api"}
+	if path, ok := (Settings{CloneRoot: "/src"}).LegacyRepoPath(Account{}, repo); !ok || path != "/src/api" {
+		t.Errorf("LegacyRepoPath = %q, %v", path, ok)
+	}
+	if _, ok := (Settings{CloneTemplate: "{root}/{name}"}).LegacyRepoPath(Account{}, repo); ok {
+		t.Error("legacy path used with a configured template")
+	}
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+// Ключи сортировки списка репозиториев. Пустой ключ означает порядок API.
+const (
+	SortDefault = ""
+	SortName    = "name"
+	SortUpdated = "updated"
+	SortPushed  = "pushed"
+	SortStars   = "stars"
+	SortForks   = "forks"
+	SortSize    = "size"
+	SortOwner   = "owner"
+)
+
+// SortKeys ключи сортировки в порядке переключения
+var SortKeys = []string{SortDefault, SortName, SortUpdated, SortPushed, SortStars, SortForks, SortSize, SortOwner}
+
+// NextSortKey возвращает ключ сортировки, следующий за key
+func NextSortKey(key string) string {
+	for i, k := range SortKeys {
+		if k == key {
+			return SortKeys[(i+1)%len(SortKeys)]
+		}
+	}
+	return SortDefault
+}
+
+// SortLabel возвращает описание сортировки для заголовка списка
+func SortLabel(key string, desc bool) string {
+	if key == SortDefault {
+		key = "API order"
+	}
+	if desc {
+		return key + " ↓"
+	}
+	return key + " ↑"
+}
+
+// SortRepos возвращает отсортированную копию списка репозиториев.
+// Репозитории с равными значениями ключа упорядочиваются по владельцу и имени.
+func SortRepos(repos []Repository, key string, desc bool) []Repository {
+	sorted := append([]Repository(nil), repos...)
+	if key == SortDefault {
+		if desc {
+			for i, j := 0, len(sorted)-1; i < j; i, j = i+1, j-1 {
+				sorted[i], sorted[j] = sorted[j], sorted[i]
+			}
+		}
+		return sorted
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		c := compareRepos(a, b, key)
+		if desc {
+			c = -c
+		}
+		if c != 0 {
+			return c < 0
+		}
+		return compareRepos(a, b, SortOwner) < 0
+	})
+	return sorted
+}
+
+// compareRepos сравнивает репозитории по ключу сортировки
+func compareRepos(a, b Repository, key string) int {
+	switch key {
+	case SortName:
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	case SortUpdated:
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	case SortPushed:
+		return a.PushedAt.Compare(b.PushedAt)
+	case SortStars:
+		return a.Stars - b.Stars
+	case SortForks:
+		return a.Forks - b.Forks
+	case SortSize:
+		return a.Size - b.Size
+	case SortOwner:
+		if c := strings.Compare(strings.ToLower(a.Owner), strings.ToLower(b.Owner)); c != 0 {
+			return c
+		}
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	}
+	return 0
+}
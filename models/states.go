@@ -3,12 +3,28 @@ package models
 // Состояния формы добавления аккаунта
 const (
 	NameInput = iota
+	APIURLInput
 	TokenInput
 )
 
+// Состояния формы ввода парольной фразы
+const (
+	PassphraseInput = iota
+	PassphraseConfirmInput
+)
+
 // Состояния приложения
 const (
 	StateAccounts = iota
 	StateRepos
 	StateAddingAccount
+	StateUnlock
+	StateConfirmDelete
+	StateRenameAccount
+	StateRotateToken
+	StateCloneQueue
+	StateConfirmSync
+	StateOwnerPicker
+	StateRepoDetail
+	StateAccountSettings
 )
@@ -0,0 +1,280 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/KharpukhaevV/gitui/config"
+	"github.com/KharpukhaevV/gitui/models"
+	"github.com/KharpukhaevV/gitui/utils"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// accountSetting поле формы настроек аккаунта
+type accountSetting struct {
+	label string
+	get   func(models.Account) string
+	set   func(*models.Account, string)
+}
+
+// accountSettings поля формы настроек аккаунта в порядке отображения
+var accountSettings = []accountSetting{
+	{
+		label: "Git user.name",
+		get:   func(a models.Account) string { return a.GitName },
+		set:   func(a *models.Account, value string) { a.GitName = value },
+	},
+	{
+		label: "Git user.email",
+		get:   func(a models.Account) string { return a.GitEmail },
+		set:   func(a *models.Account, value string) { a.GitEmail = value },
+	},
+	{
+		label: "Signing key (GPG key ID or SSH .pub key)",
+		get:   func(a models.Account) string { return a.SigningKey },
+		set:   func(a *models.Account, value string) { a.SigningKey = value },
+	},
+	{
+		label: "SSH key (private key path)",
+		get:   func(a models.Account) string { return a.SSHKey },
+		set:   func(a *models.Account, value string) { a.SSHKey = value },
+	},
+	{
+		label: "SSH host (alias from ~/.ssh/config)",
+		get:   func(a models.Account) string { return a.SSHHost },
+		set:   func(a *models.Account, value string) { a.SSHHost = value },
+	},
+}
+
+// pendingCommit изменения аккаунтов, ожидающие ввода парольной фразы
+type pendingCommit struct {
+	accounts []models.Account
+	selected int
+	message  string
+	after    func() error
+}
+
+// commitAccounts сохраняет новый список аккаунтов и возвращает к экрану аккаунтов.
+// Если для шифрования токена нужна парольная фраза, открывает экран ее ввода.
+// after, если задан, выполняется только после успешного сохранения.
+func (m *AppModel) commitAccounts(accounts []models.Account, selected int, message string, after func() error) {
+	if err := m.ConfigManager.SaveAccounts(accounts); err != nil {
+		if errors.Is(err, config.ErrLocked) {
+			m.pending = &pendingCommit{accounts: accounts, selected: selected, message: message, after: after}
+			m.State = models.StateUnlock
+			m.resetUnlockForm()
+			if m.ConfigManager.HasPassphrase() {
+				m.Message = "Enter the passphrase to encrypt the token"
+			} else {
+				m.Message = "Set a passphrase to encrypt the token"
+			}
+			m.MessageType = "success"
+			return
+		}
+		m.Message = fmt.Sprintf("Error saving accounts: %v", err)
+		m.MessageType = "error"
+	} else {
+		m.Message = message
+		m.MessageType = "success"
+		if after != nil {
+			if err := after(); err != nil {
+				m.Message = fmt.Sprintf("%s, but %v", message, err)
+				m.MessageType = "warning"
+			}
+		}
+	}
+
+	m.Accounts = accounts
+	m.SelectedAccountPtr = nil
+	m.refreshAccountsList()
+	m.SelectedAccount = utils.Min(utils.Max(selected, 0), len(m.AccountsList)-1)
+	m.State = models.StateAccounts
+}
+
+// accountFromTokenInput подставляет токен из поля ввода в копию аккаунта.
+// Токен может быть ссылкой на внешнее хранилище (например, "env:GITHUB_TOKEN").
+func (m *AppModel) accountFromTokenInput(account models.Account) (models.Account, error) {
+	value := m.TokenInput.Value()
+	account.Token = value
+	account.TokenBackend = ""
+	account.TokenRef = ""
+
+	if backend, ref, ok := m.ConfigManager.ParseTokenRef(value); ok {
+		token, err := m.ConfigManager.ResolveToken(backend, ref)
+		if err != nil {
+			return account, fmt.Errorf("error reading token from %s: %v", backend, err)
+		}
+		account.Token = token
+		account.TokenBackend = backend
+		account.TokenRef = ref
+	}
+	if err := account.Connect(); err != nil {
+		return account, fmt.Errorf("error connecting to %s: %v", account.APIURL, err)
+	}
+	return account, nil
+}
+
+// accountNameTaken проверяет, занято ли имя другим аккаунтом
+func (m *AppModel) accountNameTaken(name string, except int) bool {
+	for i, acc := range m.Accounts {
+		if i != except && strings.EqualFold(acc.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// startAccountEdit открывает форму изменения выбранного аккаунта
+func (m *AppModel) startAccountEdit(state int) {
+	m.EditingAccount = m.SelectedAccount
+	m.State = state
+	m.Message = ""
+	m.resetAccountForm()
+
+	switch state {
+	case models.StateRenameAccount:
+		m.NameInput.SetValue(m.Accounts[m.EditingAccount].Name)
+		m.NameInput.CursorEnd()
+	case models.StateRotateToken:
+		m.FormState = models.TokenInput
+		m.NameInput.Blur()
+		m.TokenInput.Focus()
+	case models.StateAccountSettings:
+		m.NameInput.Blur()
+		m.SettingInputs = make([]textinput.Model, len(accountSettings))
+		for i, setting := range accountSettings {
+			m.SettingInputs[i] = textinput.New()
+			m.SettingInputs[i].SetValue(setting.get(m.Accounts[m.EditingAccount]))
+		}
+		m.FormState = 0
+		m.SettingInputs[0].Focus()
+	}
+}
+
+// updateConfirmDeleteState обновление состояния подтверждения удаления аккаунта
+func (m *AppModel) updateConfirmDeleteState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "y", "Y":
+		account := m.Accounts[m.EditingAccount]
+		accounts := append(append([]models.Account{}, m.Accounts[:m.EditingAccount]...), m.Accounts[m.EditingAccount+1:]...)
+		// Кэш и токен удаленного аккаунта больше не нужны
+		m.commitAccounts(accounts, m.EditingAccount-1, fmt.Sprintf("Account %s deleted", account.Name), func() error {
+			m.GitHubClient.ForgetAccount(account)
+			return m.ConfigManager.DeleteToken(account, accounts)
+		})
+	case "n", "N", "esc":
+		m.State = models.StateAccounts
+	}
+	return m, nil
+}
+
+// updateRenameAccountState обновление состояния переименования аккаунта
+func (m *AppModel) updateRenameAccountState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.State = models.StateAccounts
+		m.resetAccountForm()
+	case "enter":
+		name := strings.TrimSpace(m.NameInput.Value())
+		if name == "" {
+			return m, nil
+		}
+		if m.accountNameTaken(name, m.EditingAccount) {
+			m.Message = fmt.Sprintf("Account %s already exists", name)
+			m.MessageType = "error"
+			return m, nil
+		}
+
+		accounts := append([]models.Account{}, m.Accounts...)
+		account := accounts[m.EditingAccount]
+		accounts[m.EditingAccount].Name = name
+		m.resetAccountForm()
+		// Кэш и лимиты запросов хранятся по имени аккаунта
+		m.commitAccounts(accounts, m.EditingAccount, fmt.Sprintf("Account %s renamed to %s", account.Name, name), func() error {
+			m.GitHubClient.RenameAccount(account, name)
+			return nil
+		})
+	default:
+		m.NameInput, _ = m.NameInput.Update(msg)
+	}
+	return m, nil
+}
+
+// updateRotateTokenState обновление состояния замены токена аккаунта
+func (m *AppModel) updateRotateTokenState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, tea.Quit
+	case msg.String() == "esc":
+		m.State = models.StateAccounts
+		m.Loading = false
+		m.validateReq.stop()
+		m.resetAccountForm()
+	case m.Loading:
+		// Ждем завершения проверки токена
+	case msg.String() == "enter":
+		if m.TokenInput.Value() == "" {
+			return m, nil
+		}
+		account, err := m.accountFromTokenInput(m.Accounts[m.EditingAccount])
+		if err != nil {
+			m.Message = err.Error()
+			m.MessageType = "error"
+			return m, nil
+		}
+
+		// Проверяем новый токен перед сохранением
+		m.Loading = true
+		m.Message = ""
+		ctx, id := m.validateReq.start()
+		return m, tea.Batch(m.Spinner.Tick, m.GitHubClient.ValidateAccount(ctx, id, account))
+	default:
+		m.TokenInput, _ = m.TokenInput.Update(msg)
+	}
+	return m, nil
+}
+
+// rotateToken заменяет токен редактируемого аккаунта проверенным
+func (m *AppModel) rotateToken(account models.Account) {
+	accounts := append([]models.Account{}, m.Accounts...)
+	accounts[m.EditingAccount] = account
+	m.commitAccounts(accounts, m.EditingAccount, fmt.Sprintf("Token for %s updated", account.Name), nil)
+}
+
+// updateAccountSettingsState обновление состояния формы настроек аккаунта
+func (m *AppModel) updateAccountSettingsState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.State = models.StateAccounts
+		m.SettingInputs = nil
+	case "tab", "down":
+		m.focusSetting(m.FormState + 1)
+	case "shift+tab", "up":
+		m.focusSetting(m.FormState - 1)
+	case "enter":
+		accounts := append([]models.Account{}, m.Accounts...)
+		for i, setting := range accountSettings {
+			setting.set(&accounts[m.EditingAccount], strings.TrimSpace(m.SettingInputs[i].Value()))
+		}
+		m.SettingInputs = nil
+		m.commitAccounts(accounts, m.EditingAccount, fmt.Sprintf("Settings for %s saved", accounts[m.EditingAccount].Name), nil)
+	default:
+		m.SettingInputs[m.FormState], _ = m.SettingInputs[m.FormState].Update(msg)
+	}
+	return m, nil
+}
+
+// focusSetting переводит фокус на поле формы настроек, по кругу
+func (m *AppModel) focusSetting(field int) {
+	m.SettingInputs[m.FormState].Blur()
+	m.FormState = (field + len(m.SettingInputs)) % len(m.SettingInputs)
+	m.SettingInputs[m.FormState].Focus()
+}
@@ -0,0 +1,468 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KharpukhaevV/gitui/config"
+	"github.com/KharpukhaevV/gitui/models"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/muesli/termenv"
+)
+
+// Размер терминала в тестах
+const (
+	termWidth  = 100
+	termHeight = 30
+)
+
+// testTokenEnv переменная окружения с токеном для добавления аккаунта в тестах
+const testTokenEnv = "GITUI_TEST_TOKEN"
+
+func TestMain(m *testing.M) {
+	// Снимки экранов не должны зависеть от терминала, в котором запущены тесты
+	lipgloss.SetColorProfile(termenv.Ascii)
+	lipgloss.SetHasDarkBackground(true)
+	os.Exit(m.Run())
+}
+
+// testRepos репозитории аккаунтов в тестах
+func testRepos() map[string][]models.Repository {
+	updated := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	return map[string][]models.Repository{
+		"work": {
+			{Owner: "acme", Name: "api", Desc: "Public API", Language: "Go", Stars: 42, Forks: 3, IsPrivate: true, UpdatedAt: updated, Host: models.DefaultHost},
+			{Owner: "acme", Name: "web", Desc: "Web client", Language: "TypeScript", Stars: 7, UpdatedAt: updated.AddDate(0, 1, 0), Host: models.DefaultHost},
+			{Owner: "octocat", Name: "dotfiles", Language: "Shell", Stars: 1, UpdatedAt: updated.AddDate(0, -1, 0), Host: models.DefaultHost},
+		},
+		"personal": {
+			{Owner: "octocat", Name: "dotfiles", Language: "Shell", Stars: 1, UpdatedAt: updated.AddDate(0, -1, 0), Host: models.DefaultHost},
+			{Owner: "octocat", Name: "blog", Desc: "Personal blog", Language: "Go", UpdatedAt: updated, Host: models.DefaultHost},
+		},
+	}
+}
+
+// writeConfig записывает файл конфигурации с аккаунтами без токенов в домашнюю
+// директорию теста. Директория клонирования не существует, чтобы снимки не зависели от машины.
+func writeConfig(t *testing.T, names ...string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(testTokenEnv, "test-token")
+
+	accounts := []models.Account{}
+	for _, name := range names {
+		accounts = append(accounts, models.Account{Name: name, Created: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"version":  2,
+		"settings": models.Settings{CloneRoot: "/nonexistent/develop"},
+		"accounts": accounts,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".github_manager.json"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// startApp запускает модель приложения с фейковым доступом к GitHub в терминале фиксированного размера
+func startApp(t *testing.T, provider *fakeProvider, accounts ...string) *teatest.TestModel {
+	t.Helper()
+	writeConfig(t, accounts...)
+	m := NewAppModelWith(config.NewManager(), provider)
+	return teatest.NewTestModel(t, m, teatest.WithInitialTermSize(termWidth, termHeight))
+}
+
+// press отправляет нажатия клавиш. Специальные клавиши задаются именами (enter, esc, down, up),
+// остальные строки вводятся одним сообщением, как при вставке: фильтр списка считается
+// асинхронно, и при посимвольном вводе результат для части запроса может прийти последним.
+func press(tm *teatest.TestModel, keys ...string) {
+	special := map[string]tea.KeyType{
+		"enter": tea.KeyEnter,
+		"esc":   tea.KeyEscape,
+		"down":  tea.KeyDown,
+		"up":    tea.KeyUp,
+		"tab":   tea.KeyTab,
+	}
+	for _, key := range keys {
+		if keyType, ok := special[key]; ok {
+			tm.Send(tea.KeyMsg{Type: keyType})
+			continue
+		}
+		tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+}
+
+// waitFor ждет появления текста на экране
+func waitFor(t *testing.T, tm *teatest.TestModel, text string) {
+	t.Helper()
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return strings.Contains(string(out), text)
+	}, teatest.WithDuration(5*time.Second), teatest.WithCheckInterval(10*time.Millisecond))
+}
+
+// finish завершает программу и возвращает итоговое состояние модели.
+// Сообщения обрабатываются по порядку, поэтому все отправленные клавиши уже учтены.
+func finish(t *testing.T, tm *teatest.TestModel) *AppModel {
+	t.Helper()
+	if err := tm.Quit(); err != nil {
+		t.Fatal(err)
+	}
+	return tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(*AppModel)
+}
+
+// requireScreen сравнивает экран с golden-файлом testdata/<имя теста>.golden.
+// Файлы обновляются запуском go test ./ui -update.
+func requireScreen(t *testing.T, screen string) {
+	t.Helper()
+	teatest.RequireEqualOutput(t, []byte(screen))
+}
+
+func TestAccountsScreen(t *testing.T) {
+	t.Run("initial", func(t *testing.T) {
+		tm := startApp(t, newFakeProvider(nil), "personal", "work")
+		m := finish(t, tm)
+
+		if m.State != models.StateAccounts || m.SelectedAccount != 0 {
+			t.Errorf("State = %d, SelectedAccount = %d", m.State, m.SelectedAccount)
+		}
+		want := []string{"personal", "work", "★ All accounts", "+ Add Account"}
+		if strings.Join(m.AccountsList, "|") != strings.Join(want, "|") {
+			t.Errorf("AccountsList = %q, want %q", m.AccountsList, want)
+		}
+		requireScreen(t, RenderAccountsScreen(m))
+	})
+
+	t.Run("navigate", func(t *testing.T) {
+		tm := startApp(t, newFakeProvider(nil), "personal", "work")
+		press(tm, "down", "down", "down", "down", "up")
+		m := finish(t, tm)
+
+		// Курсор не выходит за последний пункт
+		if m.SelectedAccount != 2 {
+			t.Errorf("SelectedAccount = %d, want 2", m.SelectedAccount)
+		}
+		requireScreen(t, RenderAccountsScreen(m))
+	})
+
+	t.Run("single account", func(t *testing.T) {
+		tm := startApp(t, newFakeProvider(nil), "work")
+		m := finish(t, tm)
+
+		// Общий список всех аккаунтов нужен только при нескольких аккаунтах
+		if len(m.AccountsList) != 2 {
+			t.Errorf("AccountsList = %q, want the account and Add Account", m.AccountsList)
+		}
+		requireScreen(t, RenderAccountsScreen(m))
+	})
+
+	t.Run("confirm delete", func(t *testing.T) {
+		provider := newFakeProvider(nil)
+		tm := startApp(t, provider, "personal", "work")
+		press(tm, "down", "d", "y")
+		m := finish(t, tm)
+
+		if m.State != models.StateAccounts || len(m.Accounts) != 1 || m.Accounts[0].Name != "personal" {
+			t.Errorf("State = %d, Accounts = %+v", m.State, m.Accounts)
+		}
+		if m.Message != "Account work deleted" {
+			t.Errorf("Message = %q", m.Message)
+		}
+		// Кэш удаленного аккаунта удаляется вместе с ним
+		if calls := strings.Join(provider.Calls(), "\n"); !strings.Contains(calls, "ForgetAccount work") {
+			t.Errorf("calls = %q, want ForgetAccount work", calls)
+		}
+		requireScreen(t, RenderAccountsScreen(m))
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		provider := newFakeProvider(nil)
+		tm := startApp(t, provider, "personal", "work")
+		press(tm, "down", "e", "-old", "enter")
+		m := finish(t, tm)
+
+		if m.State != models.StateAccounts || m.Accounts[1].Name != "work-old" {
+			t.Errorf("State = %d, Accounts = %+v", m.State, m.Accounts)
+		}
+		// Кэш и лимиты переносятся на новое имя
+		if calls := strings.Join(provider.Calls(), "\n"); !strings.Contains(calls, "RenameAccount work work-old") {
+			t.Errorf("calls = %q, want RenameAccount work work-old", calls)
+		}
+	})
+}
+
+func TestAddAccountScreen(t *testing.T) {
+	t.Run("name", func(t *testing.T) {
+		tm := startApp(t, newFakeProvider(nil))
+		press(tm, "enter", "work")
+		m := finish(t, tm)
+
+		if m.State != models.StateAddingAccount || m.FormState != models.NameInput || m.NameInput.Value() != "work" {
+			t.Errorf("State = %d, FormState = %d, name = %q", m.State, m.FormState, m.NameInput.Value())
+		}
+		requireScreen(t, RenderAddAccountScreen(m))
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		tm := startApp(t, newFakeProvider(nil), "work")
+		press(tm, "down", "enter", "Work", "enter")
+		m := finish(t, tm)
+
+		if m.FormState != models.NameInput || m.MessageType != "error" {
+			t.Errorf("FormState = %d, message = %q (%s)", m.FormState, m.Message, m.MessageType)
+		}
+		requireScreen(t, RenderAddAccountScreen(m))
+	})
+
+	t.Run("api url", func(t *testing.T) {
+		tm := startApp(t, newFakeProvider(nil))
+		press(tm, "enter", "ghe", "enter")
+		m := finish(t, tm)
+
+		if m.FormState != models.APIURLInput {
+			t.Errorf("FormState = %d, want the API URL field", m.FormState)
+		}
+		requireScreen(t, RenderAddAccountScreen(m))
+	})
+
+	t.Run("invalid api url", func(t *testing.T) {
+		tm := startApp(t, newFakeProvider(nil))
+		press(tm, "enter", "ghe", "enter", "ghe.example.com", "enter")
+		m := finish(t, tm)
+
+		if m.FormState != models.APIURLInput || m.MessageType != "error" {
+			t.Errorf("FormState = %d, message = %q (%s)", m.FormState, m.Message, m.MessageType)
+		}
+		requireScreen(t, RenderAddAccountScreen(m))
+	})
+
+	t.Run("token", func(t *testing.T) {
+		tm := startApp(t, newFakeProvider(nil))
+		press(tm, "enter", "work", "enter", "enter", "secret")
+		m := finish(t, tm)
+
+		if m.FormState != models.TokenInput || m.TokenInput.Value() != "secret" {
+			t.Errorf("FormState = %d, token = %q", m.FormState, m.TokenInput.Value())
+		}
+		// Токен не показывается на экране
+		screen := RenderAddAccountScreen(m)
+		if strings.Contains(screen, "secret") {
+			t.Error("token is visible on the screen")
+		}
+		requireScreen(t, screen)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		provider := newFakeProvider(nil)
+		provider.validate = errors.New("token rejected by GitHub: Bad credentials")
+		tm := startApp(t, provider)
+		press(tm, "enter", "work", "enter", "enter", "env:"+testTokenEnv, "enter")
+		waitFor(t, tm, "Bad credentials")
+		m := finish(t, tm)
+
+		if m.State != models.StateAddingAccount || m.FormState != models.TokenInput || m.Loading {
+			t.Errorf("State = %d, FormState = %d, Loading = %v", m.State, m.FormState, m.Loading)
+		}
+		if len(m.Accounts) != 0 {
+			t.Errorf("account saved after a rejected token: %+v", m.Accounts)
+		}
+		requireScreen(t, RenderAddAccountScreen(m))
+	})
+
+	t.Run("save", func(t *testing.T) {
+		provider := newFakeProvider(nil)
+		tm := startApp(t, provider)
+		press(tm, "enter", "work", "enter", "enter", "env:"+testTokenEnv, "enter")
+		waitFor(t, tm, "added successfully")
+		m := finish(t, tm)
+
+		if m.State != models.StateAccounts || len(m.Accounts) != 1 || m.SelectedAccount != 0 {
+			t.Fatalf("State = %d, Accounts = %+v, SelectedAccount = %d", m.State, m.Accounts, m.SelectedAccount)
+		}
+		account := m.Accounts[0]
+		if account.Login != "octocat" || account.TokenBackend != "env" || account.TokenRef != testTokenEnv || account.Token != "test-token" {
+			t.Errorf("unexpected account: %+v", account)
+		}
+		if calls := provider.Calls(); len(calls) != 1 || calls[0] != "ValidateAccount work" {
+			t.Errorf("provider calls = %q", calls)
+		}
+
+		// Аккаунт сохранен в конфигурации
+		saved, err := config.NewManager().LoadAccounts()
+		if err != nil || len(saved) != 1 || saved[0].Name != "work" || saved[0].Login != "octocat" {
+			t.Errorf("saved accounts = %+v, err = %v", saved, err)
+		}
+		requireScreen(t, RenderAccountsScreen(m))
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		tm := startApp(t, newFakeProvider(nil), "work")
+		press(tm, "down", "enter", "tmp", "esc")
+		m := finish(t, tm)
+
+		if m.State != models.StateAccounts || m.NameInput.Value() != "" || len(m.Accounts) != 1 {
+			t.Errorf("State = %d, name = %q, Accounts = %d", m.State, m.NameInput.Value(), len(m.Accounts))
+		}
+	})
+}
+
+func TestAccountSettingsScreen(t *testing.T) {
+	t.Run("edit", func(t *testing.T) {
+		tm := startApp(t, newFakeProvider(nil), "work")
+		press(tm, "s", "Jane Doe", "tab", "jane@acme.example", "tab", "tab", "up")
+		m := finish(t, tm)
+
+		if m.State != models.StateAccountSettings || m.FormState != 2 {
+			t.Errorf("State = %d, FormState = %d", m.State, m.FormState)
+		}
+		requireScreen(t, RenderEditAccountScreen(m))
+	})
+
+	t.Run("save", func(t *testing.T) {
+		tm := startApp(t, newFakeProvider(nil), "work")
+		press(tm, "s", "Jane Doe", "tab", "jane@acme.example", "tab", "ABCDEF", "tab", "~/.ssh/id_work", "tab", "github-work", "enter")
+		m := finish(t, tm)
+
+		if m.State != models.StateAccounts || m.Message != "Settings for work saved" {
+			t.Errorf("State = %d, Message = %q", m.State, m.Message)
+		}
+		saved, err := config.NewManager().LoadAccounts()
+		if err != nil || len(saved) != 1 {
+			t.Fatalf("saved accounts = %+v, err = %v", saved, err)
+		}
+		if saved[0].GitName != "Jane Doe" || saved[0].GitEmail != "jane@acme.example" || saved[0].SigningKey != "ABCDEF" ||
+			saved[0].SSHKey != "~/.ssh/id_work" || saved[0].SSHHost != "github-work" {
+			t.Errorf("saved account = %+v", saved[0])
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		tm := startApp(t, newFakeProvider(nil), "work")
+		press(tm, "s", "Jane Doe", "esc")
+		m := finish(t, tm)
+
+		if m.State != models.StateAccounts || m.Accounts[0].GitName != "" {
+			t.Errorf("State = %d, account = %+v", m.State, m.Accounts[0])
+		}
+	})
+}
+
+func TestReposScreen(t *testing.T) {
+	t.Run("loaded", func(t *testing.T) {
+		provider := newFakeProvider(testRepos())
+		tm := startApp(t, provider, "personal", "work")
+		press(tm, "down", "enter")
+		waitFor(t, tm, "Loaded 3 repositories")
+		m := finish(t, tm)
+
+		if m.State != models.StateRepos || m.SelectedAccountPtr == nil || m.SelectedAccountPtr.Name != "work" {
+			t.Fatalf("State = %d, account = %+v", m.State, m.SelectedAccountPtr)
+		}
+		if len(m.Repos) != 3 || m.Loading || m.Refreshing || m.ReposJob != nil {
+			t.Errorf("Repos = %d, Loading = %v, Refreshing = %v, ReposJob = %v", len(m.Repos), m.Loading, m.Refreshing, m.ReposJob)
+		}
+		requireScreen(t, RenderReposScreen(m))
+	})
+
+	t.Run("sorted", func(t *testing.T) {
+		tm := startApp(t, newFakeProvider(testRepos()), "work")
+		press(tm, "enter")
+		waitFor(t, tm, "Loaded 3 repositories")
+		press(tm, "s", "s", "S")
+		waitFor(t, tm, "updated ↓")
+		m := finish(t, tm)
+
+		if m.SelectedAccountPtr.SortBy != models.SortUpdated || !m.SelectedAccountPtr.SortDesc {
+			t.Errorf("sort = %q desc=%v, want updated desc", m.SelectedAccountPtr.SortBy, m.SelectedAccountPtr.SortDesc)
+		}
+		if repo, _ := m.selectedRepo(); repo.Title() != "acme/web" {
+			t.Errorf("first repository = %s, want the most recently updated", repo.Title())
+		}
+		requireScreen(t, RenderReposScreen(m))
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		tm := startApp(t, newFakeProvider(testRepos()), "work")
+		press(tm, "enter")
+		waitFor(t, tm, "Loaded 3 repositories")
+		press(tm, "/", "is:private", "enter")
+		waitFor(t, tm, "“is:private” 1 item")
+		m := finish(t, tm)
+
+		if visible := m.List.VisibleItems(); len(visible) != 1 || visible[0].(repoItem).Title() != "  acme/api" {
+			t.Errorf("visible items = %+v, want acme/api", visible)
+		}
+		requireScreen(t, RenderReposScreen(m))
+	})
+
+	t.Run("selection", func(t *testing.T) {
+		provider := newFakeProvider(testRepos())
+		tm := startApp(t, provider, "work")
+		press(tm, "enter")
+		waitFor(t, tm, "Loaded 3 repositories")
+		press(tm, " ", "down", "down", " ")
+		waitFor(t, tm, "Selected: 2")
+		m := finish(t, tm)
+
+		repos := m.selectedRepos()
+		if len(repos) != 2 || !m.Selected[repos[0].Key()] || !m.Selected[repos[1].Key()] {
+			t.Errorf("selected repositories = %+v", repos)
+		}
+		requireScreen(t, RenderReposScreen(m))
+	})
+
+	t.Run("load error", func(t *testing.T) {
+		provider := newFakeProvider(testRepos())
+		provider.errs["work"] = errors.New("GET https://api.github.com/user/repos: 502 Bad Gateway")
+		tm := startApp(t, provider, "work")
+		press(tm, "enter")
+		waitFor(t, tm, "Error loading repositories")
+		m := finish(t, tm)
+
+		if m.State != models.StateRepos || m.MessageType != "error" || len(m.Repos) != 0 {
+			t.Errorf("State = %d, message = %q (%s), Repos = %d", m.State, m.Message, m.MessageType, len(m.Repos))
+		}
+		requireScreen(t, RenderReposScreen(m))
+	})
+
+	t.Run("all accounts", func(t *testing.T) {
+		provider := newFakeProvider(testRepos())
+		tm := startApp(t, provider, "personal", "work")
+		press(tm, "down", "down", "enter")
+		waitFor(t, tm, "Loaded 4 repositories")
+		m := finish(t, tm)
+
+		if !m.Unified || m.SelectedAccountPtr.Name != allAccountsTitle {
+			t.Errorf("Unified = %v, account = %q", m.Unified, m.SelectedAccountPtr.Name)
+		}
+		// Репозиторий, доступный обоим аккаунтам, показывается один раз
+		for _, repo := range m.Repos {
+			if repo.Title() == "octocat/dotfiles" && strings.Join(repo.Accounts, ",") != "personal,work" {
+				t.Errorf("dotfiles accounts = %q, want both", repo.Accounts)
+			}
+		}
+		requireScreen(t, RenderReposScreen(m))
+	})
+
+	t.Run("back", func(t *testing.T) {
+		tm := startApp(t, newFakeProvider(testRepos()), "personal", "work")
+		press(tm, "down", "enter")
+		waitFor(t, tm, "Loaded 3 repositories")
+		press(tm, "esc")
+		m := finish(t, tm)
+
+		if m.State != models.StateAccounts || m.SelectedAccount != 1 || m.Message != "" {
+			t.Errorf("State = %d, SelectedAccount = %d, Message = %q", m.State, m.SelectedAccount, m.Message)
+		}
+		requireScreen(t, RenderAccountsScreen(m))
+	})
+}
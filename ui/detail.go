@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/KharpukhaevV/gitui/models"
+	"github.com/KharpukhaevV/gitui/utils"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/styles"
+)
+
+// detailChrome количество строк экрана репозитория вне области прокрутки:
+// заголовок, отступы, сообщение и подсказка
+const detailChrome = 7
+
+// openDetail открывает экран репозитория и запускает загрузку его README
+func (m *AppModel) openDetail(repo models.Repository) tea.Cmd {
+	m.State = models.StateRepoDetail
+	m.Detail = repo
+	m.Readme = nil
+	m.Message = ""
+	m.Viewport = viewport.New(0, 0)
+	m.layoutDetail()
+
+	ctx, id := m.readmeReq.start()
+	return m.GitHubClient.LoadReadme(ctx, id, m.accountFor(repo), repo)
+}
+
+// layoutDetail подгоняет область прокрутки под размер окна и перерисовывает содержимое
+func (m *AppModel) layoutDetail() {
+	m.Viewport.Width = utils.Max(m.Width-AppStyle.GetHorizontalFrameSize(), 0)
+	m.Viewport.Height = utils.Max(m.Height-AppStyle.GetVerticalFrameSize()-detailChrome, 1)
+	m.Viewport.SetContent(m.detailContent())
+}
+
+// handleReadmeLoaded показывает загруженный README
+func (m *AppModel) handleReadmeLoaded(msg models.ReadmeLoadedMsg) {
+	if !m.readmeReq.current(msg.Request) {
+		return
+	}
+	m.readmeReq.stop()
+	m.Readme = &msg
+	m.Viewport.SetContent(m.detailContent())
+}
+
+// detailContent возвращает сведения о репозитории и его README
+func (m *AppModel) detailContent() string {
+	repo := m.Detail
+	doc := strings.Builder{}
+
+	if repo.Desc != "" {
+		doc.WriteString(repo.Desc + "\n\n")
+	}
+	if len(repo.Topics) > 0 {
+		doc.WriteString(fmt.Sprintf("Topics: %s\n", strings.Join(repo.Topics, ", ")))
+	}
+
+	flags := []string{"Public"}
+	if repo.IsPrivate {
+		flags[0] = "Private"
+	}
+	if repo.IsFork {
+		flags = append(flags, "fork")
+	}
+	if repo.Archived {
+		flags = append(flags, "archived")
+	}
+	doc.WriteString(fmt.Sprintf("Visibility: %s\n", strings.Join(flags, ", ")))
+	if repo.Language != "" {
+		doc.WriteString(fmt.Sprintf("Language: %s\n", repo.Language))
+	}
+	if repo.DefaultBranch != "" {
+		doc.WriteString(fmt.Sprintf("Default branch: %s\n", repo.DefaultBranch))
+	}
+	license := repo.License
+	if license == "" {
+		license = "none"
+	}
+	doc.WriteString(fmt.Sprintf("License: %s\n", license))
+	doc.WriteString(fmt.Sprintf("Size: %s\n", formatSize(repo.Size)))
+	doc.WriteString(fmt.Sprintf("⭐ %d • 🍴 %d • Open issues: %d\n", repo.Stars, repo.Forks, repo.OpenIssues))
+	doc.WriteString(fmt.Sprintf("Updated: %s", repo.UpdatedAt.Format("2006-01-02 15:04")))
+	if !repo.PushedAt.IsZero() {
+		doc.WriteString(fmt.Sprintf(" • Pushed: %s", repo.PushedAt.Format("2006-01-02 15:04")))
+	}
+	doc.WriteString("\n")
+	if repo.Homepage != "" {
+		doc.WriteString(fmt.Sprintf("Homepage: %s\n", repo.Homepage))
+	}
+	doc.WriteString(fmt.Sprintf("Web: %s\n", repo.WebURL()))
+	doc.WriteString(fmt.Sprintf("HTTPS: %s\n", repo.CloneURL))
+	doc.WriteString(fmt.Sprintf("SSH: %s\n", repo.SSHURL))
+	if len(repo.Accounts) > 0 {
+		doc.WriteString(fmt.Sprintf("Accounts: %s\n", strings.Join(repo.Accounts, ", ")))
+	}
+	if status, ok := m.LocalStatus[repo.Key()]; ok {
+		doc.WriteString(fmt.Sprintf("Local: %s\n", status))
+	}
+	doc.WriteString("\n")
+
+	switch {
+	case m.Readme == nil:
+		doc.WriteString("Loading README...")
+	case m.Readme.Err != nil:
+		doc.WriteString(ErrorStyle.Render(fmt.Sprintf("Error loading README: %v", m.Readme.Err)))
+	case m.Readme.Content == "":
+		doc.WriteString(HintStyle.Render("No README"))
+	default:
+		doc.WriteString(renderMarkdown(m.Readme.Content, m.Viewport.Width))
+	}
+	return doc.String()
+}
+
+// renderMarkdown рендерит markdown для терминала. Стиль задается переменной
+// GLAMOUR_STYLE, по умолчанию используется темная тема. Если markdown
+// не удалось отрендерить, возвращается исходный текст.
+func renderMarkdown(content string, width int) string {
+	style := os.Getenv("GLAMOUR_STYLE")
+	if style == "" {
+		style = styles.DarkStyle
+	}
+	renderer, err := glamour.NewTermRenderer(glamour.WithStylePath(style), glamour.WithWordWrap(width))
+	if err != nil {
+		return content
+	}
+	rendered, err := renderer.Render(content)
+	if err != nil {
+		return content
+	}
+	return rendered
+}
+
+// formatSize возвращает размер репозитория (в килобайтах) в читаемом виде
+func formatSize(kb int) string {
+	switch {
+	case kb >= 1024*1024:
+		return fmt.Sprintf("%.1f GB", float64(kb)/(1024*1024))
+	case kb >= 1024:
+		return fmt.Sprintf("%.1f MB", float64(kb)/1024)
+	}
+	return fmt.Sprintf("%d KB", kb)
+}
+
+// updateRepoDetailState обновление состояния экрана репозитория
+func (m *AppModel) updateRepoDetailState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.State = models.StateRepos
+		m.readmeReq.stop()
+	case "c", "C":
+		// Прогресс клонирования показывается на экране репозиториев
+		m.State = models.StateRepos
+		m.readmeReq.stop()
+		return m, m.startClone([]models.Repository{m.Detail}, msg.String() == "C")
+	default:
+		var cmd tea.Cmd
+		m.Viewport, cmd = m.Viewport.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
@@ -0,0 +1,356 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	githubClient "github.com/KharpukhaevV/gitui/github"
+	"github.com/KharpukhaevV/gitui/models"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// repoItem элемент списка репозиториев с отметкой выбора и состоянием локального клона.
+// Local равен nil, пока клон не проверен.
+type repoItem struct {
+	models.Repository
+	Selected bool
+	Local    *models.LocalStatus
+}
+
+// selectionMarkWidth ширина отметки выбора перед названием репозитория
+const selectionMarkWidth = 2
+
+// Title возвращает название репозитория с отметкой выбора
+func (i repoItem) Title() string {
+	if i.Selected {
+		return "✓ " + i.Repository.Title()
+	}
+	return "  " + i.Repository.Title()
+}
+
+// Description возвращает описание репозитория с состоянием локального клона
+// и аккаунтами с доступом к нему (в общем списке всех аккаунтов)
+func (i repoItem) Description() string {
+	desc := i.Repository.Description()
+	if len(i.Accounts) > 0 {
+		desc = "👤 " + strings.Join(i.Accounts, ", ") + " • " + desc
+	}
+	if i.Local == nil {
+		return desc
+	}
+	return i.Local.String() + " • " + desc
+}
+
+// setRepoItems перестраивает элементы списка из m.Repos в порядке сортировки аккаунта
+func (m *AppModel) setRepoItems() tea.Cmd {
+	repos := m.Repos
+	m.List.Title = "Repositories"
+	if m.SelectedAccountPtr != nil {
+		account := m.SelectedAccountPtr
+		repos = models.SortRepos(m.Repos, account.SortBy, account.SortDesc)
+		m.List.Title += " • " + models.SortLabel(account.SortBy, account.SortDesc)
+	}
+
+	items := make([]list.Item, len(repos))
+	m.itemIndex = make(map[string]int, len(repos))
+	for i, repo := range repos {
+		items[i] = m.newRepoItem(repo)
+		if _, ok := m.itemIndex[repo.Key()]; !ok {
+			m.itemIndex[repo.Key()] = i
+		}
+	}
+	m.List.Filter = repoFilter(repos)
+	return m.List.SetItems(items)
+}
+
+// repoFilter возвращает функцию фильтра для репозиториев в порядке элементов списка.
+// Условия запроса (lang:go, stars:>10 и т. п.) проверяются по полям репозитория,
+// остальной текст ищется нечетко по владельцу, имени и описанию.
+func repoFilter(repos []models.Repository) list.FilterFunc {
+	return func(term string, targets []string) []list.Rank {
+		query, err := models.ParseQuery(term)
+		if err != nil {
+			return nil
+		}
+
+		var ranks []list.Rank
+		if query.Text == "" {
+			for i := range targets {
+				ranks = append(ranks, list.Rank{Index: i})
+			}
+		} else {
+			ranks = list.DefaultFilter(query.Text, targets)
+		}
+
+		var filtered []list.Rank
+		for _, rank := range ranks {
+			if rank.Index >= len(repos) || !query.Matches(repos[rank.Index]) {
+				continue
+			}
+			// Совпадения подсвечиваются в названии, которое начинается с отметки выбора
+			for i := range rank.MatchedIndexes {
+				rank.MatchedIndexes[i] += selectionMarkWidth
+			}
+			filtered = append(filtered, rank)
+		}
+		return filtered
+	}
+}
+
+// setSort меняет сортировку списка и сохраняет ее в настройках аккаунта
+func (m *AppModel) setSort(key string, desc bool) tea.Cmd {
+	m.SelectedAccountPtr.SortBy = key
+	m.SelectedAccountPtr.SortDesc = desc
+	// Сортировка общего списка всех аккаунтов не сохраняется
+	if m.Unified {
+		return m.setRepoItems()
+	}
+	if err := m.ConfigManager.SaveAccounts(m.Accounts); err != nil {
+		m.Message = fmt.Sprintf("Sort order not saved: %v", err)
+		m.MessageType = "warning"
+	}
+	return m.setRepoItems()
+}
+
+// loadRepos показывает репозитории выбранного владельца из кэша на диске
+// и запускает их обновление из API. Без кэша показывается индикатор загрузки.
+func (m *AppModel) loadRepos() tea.Cmd {
+	m.stopRepoRequests()
+	m.Loading = false
+
+	var cmds []tea.Cmd
+	repos, fetchedAt, ok := m.GitHubClient.CachedRepos(*m.SelectedAccountPtr, m.Owner)
+	if m.Unified {
+		repos, fetchedAt, ok = m.GitHubClient.CachedAllRepos(m.Accounts)
+	}
+	if ok {
+		m.Repos = repos
+		m.ReposFetchedAt = fetchedAt
+		m.Refreshing = true
+		cmds = append(cmds, m.scanLocal(m.Repos))
+	} else {
+		m.Repos = nil
+		m.ReposFetchedAt = time.Time{}
+		m.Loading = true
+	}
+	cmds = append(cmds, m.setRepoItems(), m.Spinner.Tick, m.refreshRepos())
+	return tea.Batch(cmds...)
+}
+
+// refreshRepos запускает загрузку репозиториев выбранного владельца из API,
+// отменяя предыдущую загрузку. Без кэша список заполняется по мере загрузки страниц.
+func (m *AppModel) refreshRepos() tea.Cmd {
+	if m.ReposFetchedAt.IsZero() {
+		m.Repos = nil
+	}
+	ctx, id := m.reposReq.start()
+	if m.Unified {
+		m.ReposJob = m.GitHubClient.LoadAllRepos(ctx, id, m.Accounts)
+	} else {
+		m.ReposJob = m.GitHubClient.LoadRepos(ctx, id, *m.SelectedAccountPtr, m.Owner)
+	}
+	return m.ReposJob.Wait()
+}
+
+// stopRepoRequests отменяет загрузку репозиториев, ожидание сброса лимита
+// и сканирование клонов при уходе с экрана или смене аккаунта
+func (m *AppModel) stopRepoRequests() {
+	m.reposReq.stop()
+	m.ReposJob = nil
+	m.ownersReq.stop()
+	m.rateReq.stop()
+	m.cancelRetry()
+	m.resetScan()
+	m.Loading = false
+	m.Refreshing = false
+}
+
+// handleReposLoaded обрабатывает загруженный из API список репозиториев.
+// При ошибке остаются показанными данные из кэша. В общем списке всех аккаунтов
+// ошибка части аккаунтов не мешает показать репозитории остальных.
+func (m *AppModel) handleReposLoaded(msg models.ReposLoadedMsg) tea.Cmd {
+	// Ответ на отмененный или замененный запрос уже не нужен
+	if !m.reposReq.current(msg.Request) {
+		return nil
+	}
+	if msg.Partial {
+		return tea.Batch(m.handleReposPage(msg), m.ReposJob.Wait())
+	}
+	m.reposReq.stop()
+	m.ReposJob = nil
+	// При превышении лимита ждем его сброса и повторяем загрузку
+	if !msg.RetryAt.IsZero() {
+		m.Message = fmt.Sprintf("GitHub rate limit exceeded: %v", msg.Err)
+		m.MessageType = "warning"
+		return m.waitRetry(msg.RetryAt)
+	}
+
+	m.Loading = false
+	m.Refreshing = false
+
+	if msg.Err != nil && len(msg.Repos) == 0 {
+		if m.ReposFetchedAt.IsZero() {
+			m.Message = fmt.Sprintf("Error loading repositories: %v", msg.Err)
+			m.MessageType = "error"
+		} else {
+			m.Message = fmt.Sprintf("Refresh failed, showing cached repositories: %v", msg.Err)
+			m.MessageType = "warning"
+		}
+		return nil
+	}
+
+	cached := !m.ReposFetchedAt.IsZero()
+	m.ReposFetchedAt = msg.FetchedAt
+	if msg.NotModified && cached {
+		m.Message = fmt.Sprintf("%d repositories, no changes", len(m.Repos))
+		m.MessageType = "success"
+		return nil
+	}
+
+	// Клоны показанных по страницам репозиториев уже проверяются,
+	// остается расставить репозитории в порядке API
+	streamed := !cached && len(m.Repos) > 0
+	m.Repos = msg.Repos
+	m.Message = fmt.Sprintf("Loaded %d repositories", len(m.Repos))
+	m.MessageType = "success"
+	if msg.Err != nil {
+		m.Message = fmt.Sprintf("Loaded %d repositories, some accounts failed: %v", len(m.Repos), msg.Err)
+		m.MessageType = "warning"
+	}
+	if streamed {
+		return m.setRepoItems()
+	}
+
+	// Обновляем список и заново проверяем локальные клоны в фоне
+	m.restartScan()
+	return tea.Batch(m.setRepoItems(), m.scanLocal(m.Repos))
+}
+
+// handleReposPage показывает очередную загруженную страницу репозиториев.
+// Если показан кэш, он остается на экране до загрузки полного списка.
+func (m *AppModel) handleReposPage(msg models.ReposLoadedMsg) tea.Cmd {
+	if !m.ReposFetchedAt.IsZero() || len(msg.Repos) == 0 {
+		return nil
+	}
+	m.Loading = false
+	m.Refreshing = true
+	m.Repos = append(m.Repos, msg.Repos...)
+	return tea.Batch(m.setRepoItems(), m.scanLocal(msg.Repos))
+}
+
+// newRepoItem создает элемент списка с текущей отметкой и состоянием клона
+func (m *AppModel) newRepoItem(repo models.Repository) repoItem {
+	item := repoItem{Repository: repo, Selected: m.Selected[repo.Key()]}
+	if status, ok := m.LocalStatus[repo.Key()]; ok {
+		item.Local = &status
+	}
+	return item
+}
+
+// resetScan отменяет текущее сканирование локальных клонов и сбрасывает результаты
+func (m *AppModel) resetScan() {
+	m.restartScan()
+	m.LocalStatus = map[string]models.LocalStatus{}
+}
+
+// restartScan отменяет текущее сканирование, сохраняя уже полученные результаты
+func (m *AppModel) restartScan() {
+	m.scanReq.start()
+}
+
+// localStatusMsg результат проверки клона вместе с задачей сканирования,
+// из которой ожидается следующий результат
+type localStatusMsg struct {
+	models.LocalStatusMsg
+	job *githubClient.Job
+}
+
+// waitLocalStatus ожидает следующий результат задачи сканирования
+func waitLocalStatus(job *githubClient.Job) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := job.Wait()().(models.LocalStatusMsg)
+		if !ok {
+			return nil
+		}
+		return localStatusMsg{LocalStatusMsg: msg, job: job}
+	}
+}
+
+// scanLocal запускает фоновую проверку локальных клонов репозиториев
+func (m *AppModel) scanLocal(repos []models.Repository) tea.Cmd {
+	if m.scanReq.ctx == nil || m.SelectedAccountPtr == nil || len(repos) == 0 {
+		return nil
+	}
+	accounts, groups := m.reposByAccount(repos)
+	cmds := make([]tea.Cmd, len(accounts))
+	for i, account := range accounts {
+		cmds[i] = waitLocalStatus(m.GitHubClient.ScanLocal(m.scanReq.ctx, m.scanReq.id, groups[i], account))
+	}
+	return tea.Batch(cmds...)
+}
+
+// handleLocalStatus обновляет состояние клона в элементе списка
+// и ожидает следующий результат того же сканирования
+func (m *AppModel) handleLocalStatus(msg localStatusMsg) tea.Cmd {
+	if !m.scanReq.current(msg.Scan) {
+		return nil
+	}
+	key := msg.Repo.Key()
+	m.LocalStatus[key] = msg.Status
+	next := waitLocalStatus(msg.job)
+	if i, ok := m.itemIndex[key]; ok {
+		return tea.Batch(m.List.SetItem(i, m.newRepoItem(m.List.Items()[i].(repoItem).Repository)), next)
+	}
+	return next
+}
+
+// selectedRepo возвращает репозиторий под курсором
+func (m *AppModel) selectedRepo() (models.Repository, bool) {
+	item, ok := m.List.SelectedItem().(repoItem)
+	return item.Repository, ok
+}
+
+// toggleSelection переключает отметку репозитория под курсором
+func (m *AppModel) toggleSelection() tea.Cmd {
+	item, ok := m.List.SelectedItem().(repoItem)
+	if !ok {
+		return nil
+	}
+	if !item.Selected {
+		m.Selected[item.Key()] = true
+	} else {
+		delete(m.Selected, item.Key())
+	}
+	cmd := m.List.SetItem(m.List.GlobalIndex(), m.newRepoItem(item.Repository))
+	m.List.CursorDown()
+	return cmd
+}
+
+// selectRepos отмечает указанные элементы списка
+func (m *AppModel) selectRepos(items []list.Item) tea.Cmd {
+	for _, item := range items {
+		if repo, ok := item.(repoItem); ok {
+			m.Selected[repo.Key()] = true
+		}
+	}
+	return m.setRepoItems()
+}
+
+// clearSelection снимает все отметки
+func (m *AppModel) clearSelection() tea.Cmd {
+	m.Selected = map[string]bool{}
+	return m.setRepoItems()
+}
+
+// selectedRepos возвращает отмеченные репозитории в порядке списка
+func (m *AppModel) selectedRepos() []models.Repository {
+	var repos []models.Repository
+	for _, repo := range m.Repos {
+		if m.Selected[repo.Key()] {
+			repos = append(repos, repo)
+		}
+	}
+	return repos
+}
@@ -4,13 +4,26 @@ import "github.com/charmbracelet/bubbles/key"
 
 // KeyMap определяет клавиши навигации
 type KeyMap struct {
-	Up      key.Binding
-	Down    key.Binding
-	Quit    key.Binding
-	Submit  key.Binding
-	Refresh key.Binding
-	Clone   key.Binding
-	Back    key.Binding
+	Up        key.Binding
+	Down      key.Binding
+	Quit      key.Binding
+	Submit    key.Binding
+	Refresh   key.Binding
+	Clone     key.Binding
+	CloneAlt  key.Binding
+	Identity  key.Binding
+	Select    key.Binding
+	SelectAll key.Binding
+	ClearSel  key.Binding
+	Cancel    key.Binding
+	Queue     key.Binding
+	Sync      key.Binding
+	Owner     key.Binding
+	RateLimit key.Binding
+	Detail    key.Binding
+	Sort      key.Binding
+	Reverse   key.Binding
+	Back      key.Binding
 }
 
 // DefaultKeys возвращает стандартные клавиши
@@ -40,6 +53,58 @@ func DefaultKeys() KeyMap {
 			key.WithKeys("c"),
 			key.WithHelp("c", "clone repo"),
 		),
+		CloneAlt: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "clone via other protocol"),
+		),
+		Identity: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "apply git identity"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "select repo"),
+		),
+		SelectAll: key.NewBinding(
+			key.WithKeys("a", "A"),
+			key.WithHelp("a/A", "select visible/all"),
+		),
+		ClearSel: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "clear selection"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "cancel clone"),
+		),
+		Queue: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "show clone queue"),
+		),
+		Sync: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "sync cloned repos"),
+		),
+		Owner: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "switch owner"),
+		),
+		RateLimit: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "show rate limits"),
+		),
+		Detail: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "show repo details"),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "change sort key"),
+		),
+		Reverse: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "reverse sort order"),
+		),
 		Back: key.NewBinding(
 			key.WithKeys("esc", "backspace"),
 			key.WithHelp("esc/backspace", "back"),
@@ -1,8 +1,8 @@
 package ui
 
 import (
-	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/KharpukhaevV/gitui/config"
@@ -10,12 +10,12 @@ import (
 	"github.com/KharpukhaevV/gitui/models"
 	"github.com/KharpukhaevV/gitui/utils"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/google/go-github/github"
-	"golang.org/x/oauth2"
 )
 
 // AppModel основная модель приложения
@@ -30,68 +30,149 @@ type AppModel struct {
 	State              int
 	FormState          int
 	NameInput          textinput.Model
+	APIURLInput        textinput.Model
 	TokenInput         textinput.Model
+	PassphraseInput    textinput.Model
+	ConfirmInput       textinput.Model
+	SettingInputs      []textinput.Model
 	ConfigManager      *config.Manager
-	GitHubClient       *githubClient.Client
+	GitHubClient       githubClient.Provider
+	Settings           models.Settings
 	Repos              []models.Repository
 	SelectedAccountPtr *models.Account
+	Unified            bool
+	unifiedAccount     models.Account
+	EditingAccount     int
+	pending            *pendingCommit
 	Spinner            spinner.Model
+	Progress           progress.Model
+	CloneJob           *githubClient.Job
+	ReposJob           *githubClient.Job
+	CloneQueue         []cloneStatus
+	CloneSummary       *models.CloneQueueDoneMsg
+	QueueAction        string
+	QueueAccount       string
+	SyncRepos          []models.Repository
+	SyncReturn         int
+	Selected           map[string]bool
+	Owner              models.Owner
+	Refreshing         bool
+	RetryAt            time.Time
+	ReposFetchedAt     time.Time
+	Owners             []models.Owner
+	OwnerCursor        int
+	LocalStatus        map[string]models.LocalStatus
+	itemIndex          map[string]int
+	Detail             models.Repository
+	Readme             *models.ReadmeLoadedMsg
+	Viewport           viewport.Model
+	reposReq           request
+	ownersReq          request
+	validateReq        request
+	rateReq            request
+	retryReq           request
+	scanReq            request
+	readmeReq          request
 	Loading            bool
 	Message            string
-	MessageType        string // "success" or "error"
+	MessageType        string // "success", "warning" or "error"
 }
 
 // NewAppModel создает новую модель приложения
 func NewAppModel() *AppModel {
 	configManager := config.NewManager()
-	accounts, err := configManager.LoadAccounts()
-	if err != nil {
-		fmt.Printf("Error loading accounts: %v\n", err)
-		accounts = []models.Account{}
-	}
-
-	// Создаем список аккаунтов
-	accountsList := []string{}
-	for _, acc := range accounts {
-		accountsList = append(accountsList, acc.Name)
-	}
-	accountsList = append(accountsList, "+ Add Account")
+	return NewAppModelWith(configManager, githubClient.NewClient(configManager.Settings()))
+}
 
+// NewAppModelWith создает модель приложения с указанными конфигурацией и доступом к GitHub
+func NewAppModelWith(configManager *config.Manager, provider githubClient.Provider) *AppModel {
 	// Инициализация списка
 	l := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
 	l.Title = "Repositories"
 	l.Styles.Title = TitleStyle
 	l.SetShowHelp(false)
+	l.FilterInput.CharLimit = utils.FilterCharLimit
 
 	// Инициализация полей ввода
 	nameInput := textinput.New()
 	nameInput.Placeholder = "Account Name"
 	nameInput.Focus()
 
+	apiURLInput := textinput.New()
+	apiURLInput.Placeholder = "https://github.example.com/api/v3"
+
 	tokenInput := textinput.New()
 	tokenInput.Placeholder = "GitHub Personal Access Token"
 	tokenInput.EchoMode = textinput.EchoPassword
 
+	passphraseInput := textinput.New()
+	passphraseInput.Placeholder = "Passphrase"
+	passphraseInput.EchoMode = textinput.EchoPassword
+	passphraseInput.Focus()
+
+	confirmInput := textinput.New()
+	confirmInput.Placeholder = "Repeat passphrase"
+	confirmInput.EchoMode = textinput.EchoPassword
+
 	// Инициализация спиннера
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 
-	return &AppModel{
-		Accounts:        accounts,
+	m := &AppModel{
+		Accounts:        []models.Account{},
 		SelectedAccount: 0,
-		AccountsList:    accountsList,
 		List:            l,
 		Keys:            DefaultKeys(),
 		ConfigManager:   configManager,
-		GitHubClient:    githubClient.NewClient(),
+		GitHubClient:    provider,
+		Settings:        configManager.Settings(),
 		NameInput:       nameInput,
+		APIURLInput:     apiURLInput,
 		TokenInput:      tokenInput,
+		PassphraseInput: passphraseInput,
+		ConfirmInput:    confirmInput,
 		State:           models.StateAccounts,
 		Spinner:         s,
+		Progress:        progress.New(progress.WithDefaultGradient()),
+		Selected:        map[string]bool{},
+	}
+
+	if configManager.NeedsUnlock() {
+		m.State = models.StateUnlock
+		m.FormState = models.PassphraseInput
+	} else {
+		m.loadAccounts()
+	}
+	m.refreshAccountsList()
+	return m
+}
+
+// loadAccounts загружает аккаунты из конфигурации
+func (m *AppModel) loadAccounts() {
+	accounts, err := m.ConfigManager.LoadAccounts()
+	if err != nil {
+		m.Message = fmt.Sprintf("Error loading accounts: %v", err)
+		m.MessageType = "error"
+	}
+	if accounts != nil {
+		m.Accounts = accounts
 	}
 }
 
+// refreshAccountsList перестраивает список названий аккаунтов.
+// При нескольких аккаунтах после них идет общий список всех аккаунтов.
+func (m *AppModel) refreshAccountsList() {
+	m.AccountsList = []string{}
+	for _, acc := range m.Accounts {
+		m.AccountsList = append(m.AccountsList, acc.Name)
+	}
+	if m.hasAllAccounts() {
+		m.AccountsList = append(m.AccountsList, "★ "+allAccountsTitle)
+	}
+	m.AccountsList = append(m.AccountsList, "+ Add Account")
+}
+
 // Init инициализация программы
 func (m *AppModel) Init() tea.Cmd {
 	return nil
@@ -107,7 +188,11 @@ func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.Width = msg.Width
 		m.Height = msg.Height
 		m.List.SetSize(msg.Width-AppStyle.GetHorizontalFrameSize(), msg.Height-10)
+		m.Progress.Width = utils.Min(utils.DefaultProgressWidth, msg.Width-AppStyle.GetHorizontalFrameSize())
 		InputStyle = InputStyle.Width(utils.Min(utils.DefaultInputWidth, msg.Width-utils.MinInputWidth))
+		if m.State == models.StateRepoDetail {
+			m.layoutDetail()
+		}
 
 	case tea.KeyMsg:
 		switch m.State {
@@ -117,38 +202,74 @@ func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateReposState(msg)
 		case models.StateAddingAccount:
 			return m.updateAddingAccountState(msg)
+		case models.StateUnlock:
+			return m.updateUnlockState(msg)
+		case models.StateConfirmDelete:
+			return m.updateConfirmDeleteState(msg)
+		case models.StateRenameAccount:
+			return m.updateRenameAccountState(msg)
+		case models.StateRotateToken:
+			return m.updateRotateTokenState(msg)
+		case models.StateAccountSettings:
+			return m.updateAccountSettingsState(msg)
+		case models.StateCloneQueue:
+			return m.updateCloneQueueState(msg)
+		case models.StateConfirmSync:
+			return m.updateConfirmSyncState(msg)
+		case models.StateOwnerPicker:
+			return m.updateOwnerPickerState(msg)
+		case models.StateRepoDetail:
+			return m.updateRepoDetailState(msg)
 		}
 
 	case models.ReposLoadedMsg:
-		m.Loading = false
-		if msg.Err != nil {
-			m.Message = fmt.Sprintf("Error loading repositories: %v", msg.Err)
-			m.MessageType = "error"
-		} else {
-			m.Repos = msg.Repos
-			// Обновляем список
-			items := make([]list.Item, len(m.Repos))
-			for i, repo := range m.Repos {
-				items[i] = repo
-			}
-			m.List.SetItems(items)
-			m.Message = fmt.Sprintf("Loaded %d repositories", len(m.Repos))
-			m.MessageType = "success"
+		return m, m.handleReposLoaded(msg)
+
+	case retryTickMsg:
+		return m, m.handleRetryTick(msg)
+
+	case models.RateLimitMsg:
+		if m.State == models.StateRepos && m.SelectedAccountPtr != nil && msg.Account == m.SelectedAccountPtr.Name {
+			m.handleRateLimit(msg)
 		}
 
+	case models.OwnersLoadedMsg:
+		m.handleOwnersLoaded(msg)
+
+	case models.ReadmeLoadedMsg:
+		m.handleReadmeLoaded(msg)
+
+	case localStatusMsg:
+		return m, m.handleLocalStatus(msg)
+
+	case models.AccountValidatedMsg:
+		m.handleAccountValidated(msg)
+
+	case models.CloneProgressMsg:
+		return m, m.handleCloneProgress(msg)
+
 	case models.CloneMsg:
+		return m, m.handleCloneResult(msg)
+
+	case models.CloneQueueDoneMsg:
+		return m, m.handleCloneQueueDone(msg)
+
+	case models.IdentityAppliedMsg:
 		m.Loading = false
-		if msg.Success {
-			m.Message = fmt.Sprintf("✅ Successfully cloned %s/%s\n📁 Path: %s",
-				msg.Repo.Owner, msg.Repo.Name, msg.Path)
-			m.MessageType = "success"
-		} else {
-			m.Message = fmt.Sprintf("❌ Error cloning repository: %v", msg.Err)
+		if msg.Err != nil {
+			m.Message = fmt.Sprintf("Error applying git identity: %v", msg.Err)
 			m.MessageType = "error"
+		} else {
+			m.Message = fmt.Sprintf("Git identity applied to %d cloned repositories", msg.Applied)
+			m.MessageType = "success"
+			if msg.Skipped > 0 {
+				m.Message += fmt.Sprintf(", skipped %d with a different remote", msg.Skipped)
+				m.MessageType = "warning"
+			}
 		}
 	}
 
-	if m.Loading {
+	if m.Loading || m.Refreshing {
 		var spinCmd tea.Cmd
 		m.Spinner, spinCmd = m.Spinner.Update(msg)
 		cmds = append(cmds, spinCmd)
@@ -167,6 +288,20 @@ func (m *AppModel) View() string {
 		return RenderAddAccountScreen(m)
 	case models.StateRepos:
 		return RenderReposScreen(m)
+	case models.StateUnlock:
+		return RenderUnlockScreen(m)
+	case models.StateConfirmDelete:
+		return RenderConfirmDeleteScreen(m)
+	case models.StateRenameAccount, models.StateRotateToken, models.StateAccountSettings:
+		return RenderEditAccountScreen(m)
+	case models.StateCloneQueue:
+		return RenderCloneQueueScreen(m)
+	case models.StateConfirmSync:
+		return RenderConfirmSyncScreen(m)
+	case models.StateOwnerPicker:
+		return RenderOwnerPickerScreen(m)
+	case models.StateRepoDetail:
+		return RenderRepoDetailScreen(m)
 	default:
 		return RenderAccountsScreen(m)
 	}
@@ -187,37 +322,134 @@ func (m *AppModel) updateAccountsState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.State = models.StateAddingAccount
 			m.FormState = models.NameInput
 			m.NameInput.Focus()
-		} else if m.SelectedAccount < len(m.Accounts) {
-			// Загрузка репозиториев выбранного аккаунта
-			m.SelectedAccountPtr = &m.Accounts[m.SelectedAccount]
+		} else if m.SelectedAccount <= len(m.Accounts) {
+			// Загрузка репозиториев выбранного аккаунта или общего списка всех аккаунтов
+			// (пункт сразу после аккаунтов). Очередь клонирования другого аккаунта отменяется.
+			m.Unified = m.SelectedAccount == len(m.Accounts)
+			if m.Unified {
+				m.unifiedAccount.Name = allAccountsTitle
+				m.SelectedAccountPtr = &m.unifiedAccount
+			} else {
+				m.SelectedAccountPtr = &m.Accounts[m.SelectedAccount]
+			}
+			if m.CloneJob != nil && m.QueueAccount != m.SelectedAccountPtr.Name {
+				m.CloneJob.Cancel()
+			}
+			m.Selected = map[string]bool{}
+			m.Owner = models.Owner{}
 			m.State = models.StateRepos
-			m.Loading = true
-			return m, tea.Batch(m.Spinner.Tick, m.GitHubClient.LoadRepos(m.SelectedAccountPtr))
+			return m, m.loadRepos()
 		}
+	case msg.String() == "d" && m.SelectedAccount < len(m.Accounts):
+		m.EditingAccount = m.SelectedAccount
+		m.State = models.StateConfirmDelete
+		m.Message = ""
+	case msg.String() == "e" && m.SelectedAccount < len(m.Accounts):
+		m.startAccountEdit(models.StateRenameAccount)
+	case msg.String() == "t" && m.SelectedAccount < len(m.Accounts):
+		m.startAccountEdit(models.StateRotateToken)
+	case msg.String() == "s" && m.SelectedAccount < len(m.Accounts):
+		m.startAccountEdit(models.StateAccountSettings)
 	}
 	return m, nil
 }
 
 // updateReposState обновление состояния репозиториев
 func (m *AppModel) updateReposState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Пока вводится фильтр, все клавиши обрабатывает список
+	if m.List.SettingFilter() {
+		var cmd tea.Cmd
+		m.List, cmd = m.List.Update(msg)
+		return m, cmd
+	}
+
 	switch {
 	case msg.String() == "esc" || msg.String() == "backspace":
 		m.State = models.StateAccounts
 		m.Message = ""
+		m.stopRepoRequests()
 	case msg.String() == "ctrl+c" || msg.String() == "q":
 		return m, tea.Quit
 	case msg.String() == "r":
+		if m.Loading || m.Refreshing {
+			return m, nil
+		}
+		m.Refreshing = true
+		return m, tea.Batch(m.Spinner.Tick, m.refreshRepos())
+	case msg.String() == "L":
+		if m.singleAccountOnly("show its rate limits") {
+			return m, nil
+		}
+		ctx, _ := m.rateReq.start()
+		return m, m.GitHubClient.RateLimits(ctx, *m.SelectedAccountPtr)
+	case msg.String() == "o":
+		if m.singleAccountOnly("switch owner") {
+			return m, nil
+		}
+		m.State = models.StateOwnerPicker
+		m.Owners = nil
 		m.Loading = true
-		return m, tea.Batch(m.Spinner.Tick, m.GitHubClient.LoadRepos(m.SelectedAccountPtr))
-	case msg.String() == "c":
-		if selectedItem := m.List.SelectedItem(); selectedItem != nil {
-			if repo, ok := selectedItem.(models.Repository); ok {
-				m.Loading = true
-				return m, tea.Batch(m.Spinner.Tick, m.GitHubClient.CloneRepo(repo, m.SelectedAccountPtr.Token))
+		ctx, id := m.ownersReq.start()
+		return m, tea.Batch(m.Spinner.Tick, m.GitHubClient.LoadOwners(ctx, id, *m.SelectedAccountPtr))
+	case msg.String() == "i":
+		if m.singleAccountOnly("apply its git identity") {
+			return m, nil
+		}
+		m.Loading = true
+		return m, tea.Batch(m.Spinner.Tick, m.GitHubClient.ApplyIdentityToCloned(m.Repos, *m.SelectedAccountPtr))
+	case msg.String() == "x":
+		if m.CloneJob != nil {
+			m.CloneJob.Cancel()
+		}
+	case msg.String() == "c" || msg.String() == "C":
+		// C клонирует по протоколу, альтернативному протоколу аккаунта.
+		// Клонируем отмеченные репозитории, а если их нет — репозиторий под курсором
+		repos := m.selectedRepos()
+		if len(repos) == 0 {
+			if repo, ok := m.selectedRepo(); ok {
+				repos = append(repos, repo)
 			}
 		}
+		if len(repos) > 0 {
+			return m, m.startClone(repos, msg.String() == "C")
+		}
+	case msg.String() == "u":
+		// Синхронизация всех склонированных репозиториев аккаунта
+		var repos []models.Repository
+		accounts, groups := m.reposByAccount(m.Repos)
+		for i, account := range accounts {
+			repos = append(repos, m.GitHubClient.ClonedRepos(groups[i], account)...)
+		}
+		if len(repos) == 0 {
+			m.Message = "No cloned repositories found"
+			m.MessageType = "warning"
+			return m, nil
+		}
+		m.promptSync(repos)
+	case msg.String() == "enter":
+		if repo, ok := m.selectedRepo(); ok {
+			return m, m.openDetail(repo)
+		}
+	case msg.String() == "s":
+		return m, m.setSort(models.NextSortKey(m.SelectedAccountPtr.SortBy), m.SelectedAccountPtr.SortDesc)
+	case msg.String() == "S":
+		return m, m.setSort(m.SelectedAccountPtr.SortBy, !m.SelectedAccountPtr.SortDesc)
+	case msg.String() == " ":
+		return m, m.toggleSelection()
+	case msg.String() == "a":
+		return m, m.selectRepos(m.List.VisibleItems())
+	case msg.String() == "A":
+		return m, m.selectRepos(m.List.Items())
+	case msg.String() == "n":
+		return m, m.clearSelection()
+	case msg.String() == "p":
+		if len(m.CloneQueue) > 1 {
+			m.State = models.StateCloneQueue
+		}
 	default:
-		m.List, _ = m.List.Update(msg)
+		var cmd tea.Cmd
+		m.List, cmd = m.List.Update(msg)
+		return m, cmd
 	}
 	return m, nil
 }
@@ -225,67 +457,198 @@ func (m *AppModel) updateReposState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 // updateAddingAccountState обновление состояния добавления аккаунта
 func (m *AppModel) updateAddingAccountState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
-	case msg.String() == "esc" || msg.String() == "backspace":
+	case msg.String() == "esc":
 		m.State = models.StateAccounts
-		m.NameInput.Reset()
-		m.TokenInput.Reset()
-	case msg.String() == "ctrl+c" || msg.String() == "q":
+		m.Loading = false
+		m.validateReq.stop()
+		m.resetAccountForm()
+	case msg.String() == "ctrl+c":
 		return m, tea.Quit
+	case m.Loading:
+		// Ждем завершения проверки токена
 	case msg.String() == "enter":
 		if m.FormState == models.TokenInput {
 			// Создаем новый аккаунт
 			if m.NameInput.Value() != "" && m.TokenInput.Value() != "" {
-				ts := oauth2.StaticTokenSource(
-					&oauth2.Token{AccessToken: m.TokenInput.Value()},
-				)
-				tc := oauth2.NewClient(context.Background(), ts)
-
-				newAccount := models.Account{
-					Name:    m.NameInput.Value(),
-					Token:   m.TokenInput.Value(),
+				newAccount, err := m.accountFromTokenInput(models.Account{
+					Name:    strings.TrimSpace(m.NameInput.Value()),
+					APIURL:  strings.TrimSpace(m.APIURLInput.Value()),
 					Created: time.Now(),
-					Private: true,
-					Client:  github.NewClient(tc),
-				}
-
-				// Добавляем аккаунт и сохраняем
-				m.Accounts = append(m.Accounts, newAccount)
-				if err := m.ConfigManager.SaveAccounts(m.Accounts); err != nil {
-					m.Message = fmt.Sprintf("Error saving account: %v", err)
+				})
+				if err != nil {
+					m.Message = err.Error()
 					m.MessageType = "error"
+					return m, nil
 				}
 
-				// Обновляем список аккаунтов
-				m.AccountsList = []string{}
-				for _, acc := range m.Accounts {
-					m.AccountsList = append(m.AccountsList, acc.Name)
+				// Проверяем токен перед сохранением
+				m.Loading = true
+				m.Message = ""
+				ctx, id := m.validateReq.start()
+				return m, tea.Batch(m.Spinner.Tick, m.GitHubClient.ValidateAccount(ctx, id, newAccount))
+			}
+		} else if m.FormState == models.APIURLInput {
+			// Пустой адрес означает github.com
+			if apiURL := strings.TrimSpace(m.APIURLInput.Value()); apiURL != "" {
+				if _, _, err := models.EnterpriseURLs(apiURL); err != nil {
+					m.Message = err.Error()
+					m.MessageType = "error"
+					return m, nil
 				}
-				m.AccountsList = append(m.AccountsList, "+ Add Account")
-
-				// Переходим на новый аккаунт
-				m.SelectedAccount = len(m.Accounts) - 1
-				m.State = models.StateAccounts
-
-				// Сбрасываем поля ввода
-				m.NameInput.Reset()
-				m.TokenInput.Reset()
-				m.Message = "Account added successfully"
-				m.MessageType = "success"
 			}
-		} else {
 			// Переход к следующему полю
 			m.FormState = models.TokenInput
-			m.NameInput.Blur()
+			m.APIURLInput.Blur()
 			m.TokenInput.Focus()
+		} else {
+			if m.accountNameTaken(strings.TrimSpace(m.NameInput.Value()), -1) {
+				m.Message = fmt.Sprintf("Account %s already exists", m.NameInput.Value())
+				m.MessageType = "error"
+				return m, nil
+			}
+			// Переход к следующему полю
+			m.FormState = models.APIURLInput
+			m.Message = ""
+			m.NameInput.Blur()
+			m.APIURLInput.Focus()
 		}
 	default:
 		// Обновляем активное поле ввода
 		switch m.FormState {
 		case models.NameInput:
 			m.NameInput, _ = m.NameInput.Update(msg)
+		case models.APIURLInput:
+			m.APIURLInput, _ = m.APIURLInput.Update(msg)
 		case models.TokenInput:
 			m.TokenInput, _ = m.TokenInput.Update(msg)
 		}
 	}
 	return m, nil
 }
+
+// handleAccountValidated обрабатывает результат проверки токена нового аккаунта
+func (m *AppModel) handleAccountValidated(msg models.AccountValidatedMsg) {
+	if !m.validateReq.current(msg.Request) || (m.State != models.StateAddingAccount && m.State != models.StateRotateToken) {
+		return
+	}
+	m.validateReq.stop()
+	m.Loading = false
+
+	if msg.Err != nil {
+		m.Message = fmt.Sprintf("Invalid token: %v", msg.Err)
+		m.MessageType = "error"
+		m.TokenInput.Reset()
+		return
+	}
+
+	m.resetAccountForm()
+
+	if m.State == models.StateRotateToken {
+		m.rotateToken(msg.Account)
+	} else {
+		accounts := append(append([]models.Account{}, m.Accounts...), msg.Account)
+		m.commitAccounts(accounts, len(accounts)-1,
+			fmt.Sprintf("Account @%s added successfully", msg.Account.Login), nil)
+	}
+
+	// Предупреждаем, если токену не хватает прав для клонирования приватных репозиториев
+	if m.MessageType == "success" && msg.Account.Scopes != nil && !msg.Account.HasScope("repo") {
+		m.Message = fmt.Sprintf("Account @%s saved, but the token lacks the repo scope: private repositories cannot be cloned", msg.Account.Login)
+		m.MessageType = "warning"
+	}
+}
+
+// resetAccountForm очищает форму добавления аккаунта
+func (m *AppModel) resetAccountForm() {
+	m.NameInput.Reset()
+	m.APIURLInput.Reset()
+	m.TokenInput.Reset()
+	m.APIURLInput.Blur()
+	m.TokenInput.Blur()
+	m.NameInput.Focus()
+	m.FormState = models.NameInput
+}
+
+// updateUnlockState обновление состояния ввода парольной фразы
+func (m *AppModel) updateUnlockState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	creating := !m.ConfigManager.HasPassphrase()
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		if m.pending == nil {
+			return m, tea.Quit
+		}
+		// Отменяем изменения, ожидающие шифрования
+		m.pending = nil
+		m.resetUnlockForm()
+		m.State = models.StateAccounts
+		m.Message = "Changes were not saved"
+		m.MessageType = "error"
+	case "enter":
+		if m.PassphraseInput.Value() == "" {
+			return m, nil
+		}
+		if creating && m.FormState == models.PassphraseInput {
+			// Запрашиваем подтверждение новой парольной фразы
+			m.FormState = models.PassphraseConfirmInput
+			m.PassphraseInput.Blur()
+			m.ConfirmInput.Focus()
+			return m, nil
+		}
+		if creating && m.ConfirmInput.Value() != m.PassphraseInput.Value() {
+			m.Message = "Passphrases do not match"
+			m.MessageType = "error"
+			m.resetUnlockForm()
+			return m, nil
+		}
+
+		if err := m.ConfigManager.Unlock(m.PassphraseInput.Value()); err != nil {
+			m.Message = fmt.Sprintf("Unlock failed: %v", err)
+			m.MessageType = "error"
+			m.resetUnlockForm()
+			return m, nil
+		}
+
+		m.resetUnlockForm()
+		m.Message = ""
+		m.State = models.StateAccounts
+
+		if m.pending != nil {
+			// Сохраняем изменения, ради которых запрашивалась парольная фраза
+			pending := m.pending
+			m.pending = nil
+			m.commitAccounts(pending.accounts, pending.selected, pending.message, pending.after)
+			return m, nil
+		}
+
+		m.loadAccounts()
+		m.refreshAccountsList()
+	default:
+		switch m.FormState {
+		case models.PassphraseInput:
+			m.PassphraseInput, _ = m.PassphraseInput.Update(msg)
+		case models.PassphraseConfirmInput:
+			m.ConfirmInput, _ = m.ConfirmInput.Update(msg)
+		}
+	}
+	return m, nil
+}
+
+// resetUnlockForm очищает поля ввода парольной фразы
+func (m *AppModel) resetUnlockForm() {
+	m.PassphraseInput.Reset()
+	m.ConfirmInput.Reset()
+	m.ConfirmInput.Blur()
+	m.PassphraseInput.Focus()
+	m.FormState = models.PassphraseInput
+}
+
+// alternateProtocol возвращает протокол клонирования, противоположный указанному
+func alternateProtocol(protocol string) string {
+	if protocol == models.ProtocolSSH {
+		return models.ProtocolHTTPS
+	}
+	return models.ProtocolSSH
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KharpukhaevV/gitui/models"
+	"github.com/KharpukhaevV/gitui/utils"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// handleOwnersLoaded заполняет список владельцев для выбора.
+// Кроме пользователя и его организаций добавляются владельцы уже загруженных
+// репозиториев, к которым у пользователя есть доступ как у коллаборатора.
+func (m *AppModel) handleOwnersLoaded(msg models.OwnersLoadedMsg) {
+	if !m.ownersReq.current(msg.Request) {
+		return
+	}
+	m.ownersReq.stop()
+	m.Loading = false
+	if msg.Err != nil {
+		m.State = models.StateRepos
+		m.Message = fmt.Sprintf("Error loading organizations: %v", msg.Err)
+		m.MessageType = "error"
+		return
+	}
+
+	owners := append([]models.Owner{{}}, msg.Owners...)
+	if m.Owner.Login == "" {
+		for _, repo := range m.Repos {
+			if !ownerListed(owners, repo.Owner) {
+				owners = append(owners, models.Owner{Login: repo.Owner})
+			}
+		}
+	}
+	m.Owners = owners
+
+	m.OwnerCursor = 0
+	for i, owner := range owners {
+		if owner == m.Owner {
+			m.OwnerCursor = i
+		}
+	}
+}
+
+// ownerListed проверяет, есть ли владелец в списке
+func ownerListed(owners []models.Owner, login string) bool {
+	for _, owner := range owners {
+		if strings.EqualFold(owner.Login, login) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateOwnerPickerState обновление состояния выбора владельца репозиториев
+func (m *AppModel) updateOwnerPickerState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.State = models.StateRepos
+		m.Loading = false
+		m.ownersReq.stop()
+	case "up", "k":
+		m.OwnerCursor = utils.Max(m.OwnerCursor-1, 0)
+	case "down", "j":
+		m.OwnerCursor = utils.Min(m.OwnerCursor+1, len(m.Owners)-1)
+	case "enter":
+		if m.Loading || len(m.Owners) == 0 {
+			return m, nil
+		}
+		m.Owner = m.Owners[m.OwnerCursor]
+		m.Selected = map[string]bool{}
+		m.State = models.StateRepos
+		m.Message = ""
+		return m, m.loadRepos()
+	}
+	return m, nil
+}
@@ -0,0 +1,170 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	githubClient "github.com/KharpukhaevV/gitui/github"
+	"github.com/KharpukhaevV/gitui/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeProvider реализация githubClient.Provider без обращений к GitHub и git.
+// Репозитории и ошибки задаются для каждого аккаунта, а вызовы записываются.
+type fakeProvider struct {
+	mu       sync.Mutex
+	repos    map[string][]models.Repository
+	errs     map[string]error
+	login    string
+	fetched  time.Time
+	calls    []string
+	cloned   []githubClient.CloneTask
+	validate error
+}
+
+// newFakeProvider создает фейковый доступ к GitHub с репозиториями аккаунтов
+func newFakeProvider(repos map[string][]models.Repository) *fakeProvider {
+	return &fakeProvider{
+		repos:   repos,
+		errs:    map[string]error{},
+		login:   "octocat",
+		fetched: time.Now(),
+	}
+}
+
+// record записывает вызов метода
+func (p *fakeProvider) record(call string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, call)
+}
+
+// Calls возвращает записанные вызовы
+func (p *fakeProvider) Calls() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.calls...)
+}
+
+func (p *fakeProvider) ValidateAccount(ctx context.Context, request int, account models.Account) tea.Cmd {
+	p.record("ValidateAccount " + account.Name)
+	return func() tea.Msg {
+		if p.validate != nil {
+			return models.AccountValidatedMsg{Request: request, Account: account, Err: p.validate}
+		}
+		account.Login = p.login
+		account.Scopes = []string{"repo"}
+		account.Private = true
+		return models.AccountValidatedMsg{Request: request, Account: account}
+	}
+}
+
+func (p *fakeProvider) LoadRepos(ctx context.Context, request int, account models.Account, owner models.Owner) *githubClient.Job {
+	p.record("LoadRepos " + account.Name)
+	msg := models.ReposLoadedMsg{Request: request, Account: account.Name, Owner: owner}
+	if err := p.errs[account.Name]; err != nil {
+		msg.Err = err
+	} else {
+		msg.Repos = p.repos[account.Name]
+		msg.FetchedAt = p.fetched
+	}
+	return githubClient.NewJob(msg)
+}
+
+func (p *fakeProvider) LoadAllRepos(ctx context.Context, request int, accounts []models.Account) *githubClient.Job {
+	p.record("LoadAllRepos")
+	lists := make([][]models.Repository, len(accounts))
+	var errs []error
+	for i, account := range accounts {
+		if err := p.errs[account.Name]; err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		lists[i] = p.repos[account.Name]
+	}
+	return githubClient.NewJob(models.ReposLoadedMsg{
+		Request:   request,
+		Repos:     githubClient.MergeRepos(accounts, lists),
+		FetchedAt: p.fetched,
+		Err:       errors.Join(errs...),
+	})
+}
+
+func (p *fakeProvider) CachedRepos(account models.Account, owner models.Owner) ([]models.Repository, time.Time, bool) {
+	return nil, time.Time{}, false
+}
+
+func (p *fakeProvider) CachedAllRepos(accounts []models.Account) ([]models.Repository, time.Time, bool) {
+	return nil, time.Time{}, false
+}
+
+func (p *fakeProvider) ForgetAccount(account models.Account) {
+	p.record("ForgetAccount " + account.Name)
+}
+
+func (p *fakeProvider) RenameAccount(account models.Account, name string) {
+	p.record("RenameAccount " + account.Name + " " + name)
+}
+
+func (p *fakeProvider) LoadOwners(ctx context.Context, request int, account models.Account) tea.Cmd {
+	p.record("LoadOwners " + account.Name)
+	return func() tea.Msg {
+		return models.OwnersLoadedMsg{Request: request, Owners: []models.Owner{{Login: p.login}, {Login: "acme", Org: true}}}
+	}
+}
+
+func (p *fakeProvider) LoadReadme(ctx context.Context, request int, account models.Account, repo models.Repository) tea.Cmd {
+	p.record("LoadReadme " + repo.Title())
+	return func() tea.Msg {
+		return models.ReadmeLoadedMsg{Request: request, Repo: repo}
+	}
+}
+
+func (p *fakeProvider) RateLimit(account models.Account) (models.RateLimit, bool) {
+	return models.RateLimit{}, false
+}
+
+func (p *fakeProvider) RateLimits(ctx context.Context, account models.Account) tea.Cmd {
+	p.record("RateLimits " + account.Name)
+	return nil
+}
+
+func (p *fakeProvider) CloneQueue(tasks []githubClient.CloneTask) *githubClient.Job {
+	p.mu.Lock()
+	p.cloned = append(p.cloned, tasks...)
+	p.mu.Unlock()
+	p.record("CloneQueue")
+
+	var msgs []tea.Msg
+	done := models.CloneQueueDoneMsg{}
+	for _, task := range tasks {
+		done.Account = task.Account.Name
+		done.Cloned++
+		msgs = append(msgs, models.CloneMsg{Account: task.Account.Name, Repo: task.Repo, Action: models.ActionClone, Success: true})
+	}
+	return githubClient.NewJob(append(msgs, done)...)
+}
+
+func (p *fakeProvider) RepoPath(account models.Account, repo models.Repository) (string, error) {
+	return models.Settings{}.RepoPath(account, repo)
+}
+
+func (p *fakeProvider) ClonedRepos(repos []models.Repository, account models.Account) []models.Repository {
+	return nil
+}
+
+func (p *fakeProvider) ScanLocal(ctx context.Context, scan int, repos []models.Repository, account models.Account) *githubClient.Job {
+	return githubClient.NewJob()
+}
+
+func (p *fakeProvider) ApplyIdentityToCloned(repos []models.Repository, account models.Account) tea.Cmd {
+	p.record("ApplyIdentityToCloned " + account.Name)
+	return func() tea.Msg {
+		return models.IdentityAppliedMsg{}
+	}
+}
+
+// fakeProvider должен реализовывать Provider
+var _ githubClient.Provider = (*fakeProvider)(nil)
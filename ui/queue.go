@@ -0,0 +1,250 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	githubClient "github.com/KharpukhaevV/gitui/github"
+	"github.com/KharpukhaevV/gitui/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Состояния репозитория в очереди клонирования
+const (
+	cloneQueued   = "queued"
+	cloneRunning  = "cloning"
+	cloneDone     = "done"
+	cloneFailed   = "failed"
+	cloneCanceled = "canceled"
+	cloneExisting = "exists"
+)
+
+// queueAction подписи действия очереди для сообщений
+type queueAction struct {
+	Title  string
+	Gerund string
+	Past   string
+}
+
+// queueActions подписи действий очереди клонирования
+var queueActions = map[string]queueAction{
+	models.ActionClone: {Title: "Clone", Gerund: "Cloning", Past: "cloned"},
+	models.ActionFetch: {Title: "Fetch", Gerund: "Fetching", Past: "fetched"},
+	models.ActionPull:  {Title: "Pull", Gerund: "Pulling", Past: "pulled"},
+}
+
+// cloneStatus состояние репозитория в очереди клонирования
+type cloneStatus struct {
+	Repo    models.Repository
+	State   string
+	Phase   string
+	Percent float64
+	Err     error
+	Path    string
+}
+
+// startClone ставит репозитории в очередь клонирования.
+// alternate включает клонирование по протоколу, альтернативному протоколу аккаунта.
+func (m *AppModel) startClone(repos []models.Repository, alternate bool) tea.Cmd {
+	return m.startQueue(repos, models.ActionClone, alternate)
+}
+
+// startQueue запускает очередь клонирования или синхронизации репозиториев.
+// Каждый репозиторий обрабатывается с учетными данными своего аккаунта.
+func (m *AppModel) startQueue(repos []models.Repository, action string, alternate bool) tea.Cmd {
+	if m.CloneJob != nil {
+		m.Message = "A clone is already in progress"
+		m.MessageType = "warning"
+		return nil
+	}
+
+	tasks := make([]githubClient.CloneTask, len(repos))
+	m.CloneQueue = make([]cloneStatus, len(repos))
+	for i, repo := range repos {
+		account := m.accountFor(repo)
+		protocol := ""
+		if alternate {
+			protocol = alternateProtocol(account.Protocol())
+		}
+		tasks[i] = githubClient.CloneTask{Repo: repo, Account: account, Protocol: protocol, Action: action}
+		m.CloneQueue[i] = cloneStatus{Repo: repo, State: cloneQueued}
+	}
+	m.QueueAction = action
+	m.QueueAccount = m.SelectedAccountPtr.Name
+	m.CloneSummary = nil
+	m.Message = ""
+
+	// Для нескольких репозиториев показываем таблицу очереди
+	if len(repos) > 1 {
+		m.State = models.StateCloneQueue
+	}
+
+	m.CloneJob = m.GitHubClient.CloneQueue(tasks)
+	return m.CloneJob.Wait()
+}
+
+// cloneStatusFor возвращает состояние репозитория в очереди
+func (m *AppModel) cloneStatusFor(repo models.Repository) *cloneStatus {
+	for i := range m.CloneQueue {
+		if m.CloneQueue[i].Repo.Key() == repo.Key() {
+			return &m.CloneQueue[i]
+		}
+	}
+	return nil
+}
+
+// handleCloneProgress обновляет прогресс клонирования репозитория
+func (m *AppModel) handleCloneProgress(msg models.CloneProgressMsg) tea.Cmd {
+	if status := m.cloneStatusFor(msg.Repo); status != nil && (status.State == cloneQueued || status.State == cloneRunning) {
+		status.State = cloneRunning
+		status.Phase = msg.Phase
+		status.Percent = msg.Percent
+	}
+	if m.CloneJob != nil {
+		return m.CloneJob.Wait()
+	}
+	return nil
+}
+
+// handleCloneResult обрабатывает результат клонирования репозитория
+func (m *AppModel) handleCloneResult(msg models.CloneMsg) tea.Cmd {
+	if status := m.cloneStatusFor(msg.Repo); status != nil {
+		status.Err = msg.Err
+		status.Path = msg.Path
+		switch {
+		case msg.Canceled:
+			status.State = cloneCanceled
+		case msg.Existing:
+			status.State = cloneExisting
+		case msg.Success:
+			status.State = cloneDone
+			status.Percent = 1
+		default:
+			status.State = cloneFailed
+		}
+	}
+
+	// Для одиночного клонирования показываем результат сообщением
+	if len(m.CloneQueue) == 1 {
+		action := queueActions[msg.Action]
+		if msg.Canceled {
+			m.Message = fmt.Sprintf("%s of %s/%s canceled", action.Title, msg.Repo.Owner, msg.Repo.Name)
+			m.MessageType = "warning"
+		} else if msg.Existing {
+			m.Message = ""
+		} else if msg.Success {
+			m.Message = fmt.Sprintf("✅ Successfully %s %s/%s\n📁 Path: %s",
+				action.Past, msg.Repo.Owner, msg.Repo.Name, msg.Path)
+			m.MessageType = "success"
+			if msg.Err != nil {
+				m.Message += fmt.Sprintf("\n⚠️ %v", msg.Err)
+				m.MessageType = "warning"
+			}
+		} else {
+			m.Message = fmt.Sprintf("❌ Error %s repository: %v", strings.ToLower(action.Gerund), msg.Err)
+			m.MessageType = "error"
+		}
+	}
+
+	if m.CloneJob != nil {
+		return m.CloneJob.Wait()
+	}
+	return nil
+}
+
+// handleCloneQueueDone обрабатывает завершение очереди клонирования и заново
+// проверяет состояние клонов из очереди. Если часть репозиториев уже
+// склонирована, предлагает их синхронизировать.
+func (m *AppModel) handleCloneQueueDone(msg models.CloneQueueDoneMsg) tea.Cmd {
+	m.CloneJob = nil
+
+	if len(m.CloneQueue) > 1 {
+		action := queueActions[m.QueueAction]
+		m.CloneSummary = &msg
+		m.Message = fmt.Sprintf("%s queue finished: %d %s, %d failed, %d canceled",
+			action.Title, msg.Cloned, action.Past, msg.Failed, msg.Canceled)
+		if msg.Existing > 0 {
+			m.Message += fmt.Sprintf(", %d already cloned", msg.Existing)
+		}
+		m.MessageType = "success"
+		if msg.Failed > 0 || msg.Canceled > 0 {
+			m.MessageType = "warning"
+		}
+	}
+
+	// Очередь другого аккаунта уже не относится к показанному списку
+	if m.SelectedAccountPtr == nil || m.QueueAccount != m.SelectedAccountPtr.Name {
+		return nil
+	}
+
+	var existing []models.Repository
+	for _, status := range m.CloneQueue {
+		if status.State == cloneExisting {
+			existing = append(existing, status.Repo)
+		}
+	}
+
+	// Не прерываем пользователя, если он ушел с экрана репозиториев
+	if len(existing) > 0 && (m.State == models.StateRepos || m.State == models.StateCloneQueue) {
+		m.promptSync(existing)
+	}
+
+	repos := make([]models.Repository, len(m.CloneQueue))
+	for i, status := range m.CloneQueue {
+		repos[i] = status.Repo
+	}
+	return m.scanLocal(repos)
+}
+
+// promptSync предлагает выполнить fetch или pull для уже склонированных репозиториев
+func (m *AppModel) promptSync(repos []models.Repository) {
+	m.SyncRepos = repos
+	m.SyncReturn = m.State
+	m.State = models.StateConfirmSync
+}
+
+// updateConfirmSyncState обновление состояния выбора синхронизации склонированных репозиториев
+func (m *AppModel) updateConfirmSyncState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "f":
+		m.State = m.SyncReturn
+		return m, m.startQueue(m.SyncRepos, models.ActionFetch, false)
+	case "u":
+		m.State = m.SyncReturn
+		return m, m.startQueue(m.SyncRepos, models.ActionPull, false)
+	case "s", "esc":
+		m.State = m.SyncReturn
+		m.Message = fmt.Sprintf("Skipped %d already cloned repositories", len(m.SyncRepos))
+		m.MessageType = "warning"
+		m.SyncRepos = nil
+	}
+	return m, nil
+}
+
+// finishedClones возвращает количество завершенных репозиториев в очереди
+func (m *AppModel) finishedClones() int {
+	finished := 0
+	for _, status := range m.CloneQueue {
+		if status.State != cloneQueued && status.State != cloneRunning {
+			finished++
+		}
+	}
+	return finished
+}
+
+// updateCloneQueueState обновление состояния экрана очереди клонирования
+func (m *AppModel) updateCloneQueueState(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.State = models.StateRepos
+	case "x":
+		if m.CloneJob != nil {
+			m.CloneJob.Cancel()
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/KharpukhaevV/gitui/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// retryTickMsg ежесекундное сообщение обратного отсчета до повторной загрузки
+type retryTickMsg struct {
+	id int
+}
+
+// retryTick планирует следующее сообщение обратного отсчета
+func (m *AppModel) retryTick() tea.Cmd {
+	id := m.retryReq.id
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return retryTickMsg{id: id}
+	})
+}
+
+// waitRetry начинает ожидание сброса лимита до повторной загрузки
+func (m *AppModel) waitRetry(at time.Time) tea.Cmd {
+	m.RetryAt = at
+	m.retryReq.start()
+	return m.retryTick()
+}
+
+// cancelRetry отменяет ожидание сброса лимита
+func (m *AppModel) cancelRetry() {
+	m.RetryAt = time.Time{}
+	m.retryReq.stop()
+}
+
+// handleRetryTick обновляет обратный отсчет и повторяет загрузку после сброса лимита
+func (m *AppModel) handleRetryTick(msg retryTickMsg) tea.Cmd {
+	if !m.retryReq.current(msg.id) {
+		return nil
+	}
+	if time.Now().Before(m.RetryAt) {
+		return m.retryTick()
+	}
+	m.cancelRetry()
+	m.Message = ""
+	return m.refreshRepos()
+}
+
+// handleRateLimit показывает лимиты запросов аккаунта
+func (m *AppModel) handleRateLimit(msg models.RateLimitMsg) {
+	if msg.Err != nil {
+		m.Message = fmt.Sprintf("Error loading rate limits for %s: %v", msg.Account, msg.Err)
+		m.MessageType = "error"
+		return
+	}
+	m.Message = fmt.Sprintf("Rate limits for %s: core %s; search %s", msg.Account, msg.Core, msg.Search)
+	m.MessageType = "success"
+	if msg.Core.Low() {
+		m.MessageType = "warning"
+	}
+}
+
+// rateLimitStatus возвращает строку заголовка с лимитом запросов выбранного аккаунта
+func (m *AppModel) rateLimitStatus() string {
+	if !m.RetryAt.IsZero() {
+		wait := time.Until(m.RetryAt).Round(time.Second)
+		return WarningStyle.Render(fmt.Sprintf("⏳ Rate limited, retrying in %s", wait))
+	}
+	rate, ok := m.GitHubClient.RateLimit(*m.SelectedAccountPtr)
+	if !ok {
+		return ""
+	}
+	status := "API: " + rate.String()
+	if rate.Low() {
+		return WarningStyle.Render(status)
+	}
+	return HintStyle.Render(status)
+}
@@ -0,0 +1,32 @@
+package ui
+
+import "context"
+
+// request отменяемая фоновая операция экрана. Результаты приходят с номером
+// запроса, и ответы на отмененные или замененные запросы отбрасываются.
+type request struct {
+	id     int
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// start отменяет предыдущий запрос и начинает новый
+func (r *request) start() (context.Context, int) {
+	r.stop()
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+	return r.ctx, r.id
+}
+
+// stop отменяет текущий запрос, его результаты будут отброшены
+func (r *request) stop() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.id++
+	r.ctx, r.cancel = nil, nil
+}
+
+// current сообщает, что результат относится к текущему запросу
+func (r *request) current(id int) bool {
+	return r.ctx != nil && r.id == id
+}
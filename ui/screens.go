@@ -3,12 +3,17 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/KharpukhaevV/gitui/models"
 	"github.com/KharpukhaevV/gitui/utils"
+	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// tokenRefHint подсказка о ссылках на внешние хранилища токенов
+const tokenRefHint = "or a reference: keyring:<name>, pass:<entry>, gopass:<entry>, env:<VAR>, command:<cmd>"
+
 // RenderAccountsScreen рендерит экран выбора аккаунтов
 func RenderAccountsScreen(m *AppModel) string {
 	doc := strings.Builder{}
@@ -51,12 +56,7 @@ func RenderAccountsScreen(m *AppModel) string {
 
 	// Сообщение
 	if m.Message != "" {
-		var style lipgloss.Style
-		if m.MessageType == "success" {
-			style = SuccessStyle
-		} else {
-			style = ErrorStyle
-		}
+		style := MessageStyle(m.MessageType)
 		centeredMessage := lipgloss.Place(m.Width, 1, lipgloss.Center, lipgloss.Center, style.Render(m.Message))
 		doc.WriteString(centeredMessage + "\n\n")
 	}
@@ -65,7 +65,7 @@ func RenderAccountsScreen(m *AppModel) string {
 	instructions := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("240")).
 		Italic(true).
-		Render("Use ↑/↓ to navigate, Enter to select, q to quit")
+		Render("Use ↑/↓ to navigate, Enter to select, e rename, t rotate token, s settings, d delete, q quit")
 
 	centeredInstructions := lipgloss.Place(m.Width, 1, lipgloss.Center, lipgloss.Center, instructions)
 	doc.WriteString(centeredInstructions)
@@ -77,16 +77,40 @@ func RenderAccountsScreen(m *AppModel) string {
 func RenderReposScreen(m *AppModel) string {
 	doc := strings.Builder{}
 
-	doc.WriteString(fmt.Sprintf("Account: %s\n", m.SelectedAccountPtr.Name))
+	doc.WriteString(fmt.Sprintf("Account: %s", m.SelectedAccountPtr.Name))
+	if host := m.SelectedAccountPtr.WebHost(); host != models.DefaultHost {
+		doc.WriteString(fmt.Sprintf(" (%s)", host))
+	}
+	doc.WriteString(fmt.Sprintf(" • Owner: %s", m.Owner))
+	if !m.ReposFetchedAt.IsZero() {
+		doc.WriteString(fmt.Sprintf(" • Updated %s", formatAge(m.ReposFetchedAt)))
+	}
+	if m.Refreshing {
+		doc.WriteString(fmt.Sprintf(" • %s refreshing…", m.Spinner.View()))
+	}
+	doc.WriteString("\n")
+	if status := m.rateLimitStatus(); status != "" {
+		doc.WriteString(status + "\n")
+	}
+	if chips := m.filterChips(); chips != "" {
+		doc.WriteString(chips + "\n")
+	}
 
-	// Показываем путь develop directory
-	devPath, exists, err := utils.CheckDevelopDir()
-	if err == nil {
+	// Показываем директорию и шаблон клонирования
+	settings := m.Settings
+	template := settings.CloneTemplate
+	if template == "" {
+		template = models.DefaultCloneTemplate
+	}
+	if m.Unified {
+		doc.WriteString(fmt.Sprintf("Clone directory: per account (%s)\n\n", template))
+	} else {
+		root := settings.RootFor(*m.SelectedAccountPtr)
 		status := "✅"
-		if !exists {
+		if !utils.DirExists(root) {
 			status = "❌"
 		}
-		doc.WriteString(fmt.Sprintf("Develop directory: %s %s\n\n", devPath, status))
+		doc.WriteString(fmt.Sprintf("Clone directory: %s %s (%s)\n\n", root, status, template))
 	}
 
 	if m.Loading {
@@ -95,22 +119,225 @@ func RenderReposScreen(m *AppModel) string {
 		doc.WriteString(m.List.View() + "\n\n")
 	}
 
-	// Сообщение
-	if m.Message != "" {
-		var style lipgloss.Style
-		if m.MessageType == "success" {
-			style = SuccessStyle
+	// Прогресс клонирования
+	if m.CloneJob != nil {
+		if len(m.CloneQueue) == 1 {
+			status := m.CloneQueue[0]
+			doc.WriteString(fmt.Sprintf("%s %s/%s: %s %d%%\n", queueActions[m.QueueAction].Gerund, status.Repo.Owner, status.Repo.Name,
+				status.Phase, int(status.Percent*100)))
+			doc.WriteString(m.Progress.ViewAs(status.Percent) + "\n")
+			doc.WriteString(HintStyle.Render("Press x to cancel") + "\n\n")
 		} else {
-			style = ErrorStyle
+			doc.WriteString(fmt.Sprintf("%s %d/%d repositories...\n", queueActions[m.QueueAction].Gerund, m.finishedClones(), len(m.CloneQueue)))
+			doc.WriteString(HintStyle.Render("Press p to show the queue, x to cancel") + "\n\n")
 		}
+	}
+
+	if len(m.Selected) > 0 {
+		doc.WriteString(fmt.Sprintf("Selected: %d\n", len(m.Selected)))
+	}
+
+	// Сообщение
+	if m.Message != "" {
+		style := MessageStyle(m.MessageType)
 		doc.WriteString(style.Render(m.Message) + "\n\n")
 	}
 
-	doc.WriteString("Press c to clone, r to refresh, esc to back, q to quit")
+	if m.Unified {
+		// Каждый репозиторий клонируется через свой аккаунт и его протокол
+		doc.WriteString("Press space to select, a/A to select visible/all, n to clear selection, " +
+			"enter to show details, s/S to change/reverse sort, c to clone, C to clone via other protocol, u to sync cloned, r to refresh, esc to back, q to quit")
+	} else {
+		doc.WriteString(fmt.Sprintf("Press space to select, a/A to select visible/all, n to clear selection, "+
+			"enter to show details, s/S to change/reverse sort, c to clone (%s), C to clone via %s, u to sync cloned, o to switch owner, L to show rate limits, i to apply git identity, r to refresh, esc to back, q to quit",
+			m.SelectedAccountPtr.Protocol(), alternateProtocol(m.SelectedAccountPtr.Protocol())))
+	}
+
+	return AppStyle.Render(doc.String())
+}
+
+// RenderCloneQueueScreen рендерит экран очереди клонирования
+func RenderCloneQueueScreen(m *AppModel) string {
+	doc := strings.Builder{}
+
+	doc.WriteString(FormTitleStyle.Render(fmt.Sprintf("%s queue: %d/%d", queueActions[m.QueueAction].Title,
+		m.finishedClones(), len(m.CloneQueue))) + "\n\n")
+
+	// Выводим столько строк, сколько помещается на экране
+	rows := len(m.CloneQueue)
+	if limit := m.Height - 10; limit > 0 && rows > limit {
+		rows = limit
+	}
+	for _, status := range m.CloneQueue[:rows] {
+		line := fmt.Sprintf("%s %s/%s", cloneStateIcon(status.State), status.Repo.Owner, status.Repo.Name)
+		switch status.State {
+		case cloneRunning:
+			line += fmt.Sprintf("  %s %d%%", status.Phase, int(status.Percent*100))
+		case cloneFailed:
+			line += "  " + MessageStyle("error").Render(firstLine(status.Err.Error()))
+		case cloneExisting:
+			line += "  " + HintStyle.Render("already cloned at "+status.Path)
+		case cloneDone:
+			if status.Err != nil {
+				line += "  " + MessageStyle("warning").Render(status.Err.Error())
+			}
+		}
+		doc.WriteString(line + "\n")
+	}
+	if rows < len(m.CloneQueue) {
+		doc.WriteString(HintStyle.Render(fmt.Sprintf("... and %d more", len(m.CloneQueue)-rows)) + "\n")
+	}
+	doc.WriteString("\n")
+
+	if m.CloneSummary != nil {
+		doc.WriteString(fmt.Sprintf("Done: %d %s, %d failed, %d canceled, %d already cloned\n\n",
+			m.CloneSummary.Cloned, queueActions[m.QueueAction].Past, m.CloneSummary.Failed,
+			m.CloneSummary.Canceled, m.CloneSummary.Existing))
+	}
+
+	if m.CloneJob != nil {
+		doc.WriteString("Press x to cancel, esc to back, q to quit")
+	} else {
+		doc.WriteString("Press esc to back, q to quit")
+	}
+
+	return AppStyle.Render(doc.String())
+}
+
+// RenderOwnerPickerScreen рендерит выбор владельца репозиториев
+func RenderOwnerPickerScreen(m *AppModel) string {
+	doc := strings.Builder{}
+
+	title := TitleStyle.Render("Select Owner")
+	doc.WriteString(lipgloss.Place(m.Width, 1, lipgloss.Center, lipgloss.Center, title) + "\n\n")
+
+	var content string
+	if m.Loading {
+		content = fmt.Sprintf("%s Loading organizations...", m.Spinner.View())
+	} else {
+		var ownerItems []string
+		for i, owner := range m.Owners {
+			if i == m.OwnerCursor {
+				ownerItems = append(ownerItems, ActiveAccountStyle.Render(owner.String()))
+			} else {
+				ownerItems = append(ownerItems, AccountItemStyle.Render(owner.String()))
+			}
+		}
+		content = lipgloss.JoinVertical(lipgloss.Center, ownerItems...)
+	}
+	doc.WriteString(lipgloss.Place(m.Width, m.Height-10, lipgloss.Center, lipgloss.Center, content) + "\n\n")
+
+	instructions := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Italic(true).
+		Render("Use ↑/↓ to navigate, Enter to select, esc to back")
+	doc.WriteString(lipgloss.Place(m.Width, 1, lipgloss.Center, lipgloss.Center, instructions))
 
 	return AppStyle.Render(doc.String())
 }
 
+// RenderRepoDetailScreen рендерит экран репозитория с его README
+func RenderRepoDetailScreen(m *AppModel) string {
+	doc := strings.Builder{}
+
+	doc.WriteString(TitleStyle.Render(m.Detail.Title()) + "\n\n")
+	doc.WriteString(m.Viewport.View() + "\n\n")
+
+	if m.Message != "" {
+		style := MessageStyle(m.MessageType)
+		doc.WriteString(style.Render(m.Message))
+	}
+	doc.WriteString("\n")
+
+	doc.WriteString(HintStyle.Render(fmt.Sprintf("↑/↓ pgup/pgdn to scroll (%d%%), c to clone, C to clone via %s, esc to back, q to quit",
+		int(m.Viewport.ScrollPercent()*100), alternateProtocol(m.accountFor(m.Detail).Protocol()))))
+
+	return AppStyle.Render(doc.String())
+}
+
+// RenderConfirmSyncScreen рендерит выбор синхронизации уже склонированных репозиториев
+func RenderConfirmSyncScreen(m *AppModel) string {
+	modalContent := strings.Builder{}
+	modalContent.WriteString(FormTitleStyle.Render("Already Cloned") + "\n\n")
+	if len(m.SyncRepos) == 1 {
+		repo := m.SyncRepos[0]
+		modalContent.WriteString(fmt.Sprintf("%s/%s is already cloned", repo.Owner, repo.Name))
+		if path, err := m.GitHubClient.RepoPath(m.accountFor(repo), repo); err == nil {
+			modalContent.WriteString(fmt.Sprintf(" at %s", path))
+		}
+		modalContent.WriteString(".\n\n")
+	} else {
+		modalContent.WriteString(fmt.Sprintf("%d repositories are already cloned.\n\n", len(m.SyncRepos)))
+	}
+	modalContent.WriteString("Press f to fetch, u to pull (fast-forward only), s or esc to skip")
+
+	centeredModal := lipgloss.Place(
+		m.Width,
+		m.Height,
+		lipgloss.Center,
+		lipgloss.Center,
+		ModalStyle.Render(modalContent.String()),
+	)
+	return AppStyle.Render(centeredModal)
+}
+
+// filterChips возвращает условия активного фильтра списка в виде меток
+func (m *AppModel) filterChips() string {
+	if m.List.FilterState() == list.Unfiltered || m.List.FilterValue() == "" {
+		return ""
+	}
+	query, err := models.ParseQuery(m.List.FilterValue())
+	if err != nil {
+		return ErrorStyle.Render("Filter: " + err.Error())
+	}
+
+	var chips []string
+	for _, facet := range query.Facets {
+		chips = append(chips, ChipStyle.Render(facet.String()))
+	}
+	if query.Text != "" {
+		chips = append(chips, ChipStyle.Render(fmt.Sprintf("%q", query.Text)))
+	}
+	return "Filter: " + strings.Join(chips, " ")
+}
+
+// cloneStateIcon возвращает значок состояния клонирования
+func cloneStateIcon(state string) string {
+	switch state {
+	case cloneRunning:
+		return "⏳"
+	case cloneDone:
+		return "✅"
+	case cloneFailed:
+		return "❌"
+	case cloneCanceled:
+		return "⛔"
+	case cloneExisting:
+		return "📁"
+	}
+	return "⏸"
+}
+
+// formatAge возвращает возраст данных в читаемом виде
+func formatAge(t time.Time) string {
+	age := time.Since(t)
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	}
+	return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+}
+
+// firstLine возвращает первую строку текста
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
+
 // RenderAddAccountScreen рендерит экран добавления аккаунта
 func RenderAddAccountScreen(m *AppModel) string {
 	doc := strings.Builder{}
@@ -124,24 +351,146 @@ func RenderAddAccountScreen(m *AppModel) string {
 		formContent.WriteString("Account Name:\n")
 		formContent.WriteString(InputStyle.Render(m.NameInput.View()) + "\n\n")
 		formContent.WriteString("Press Enter to continue, esc to cancel")
+	case models.APIURLInput:
+		formContent.WriteString("GitHub Enterprise API URL:\n")
+		formContent.WriteString(InputStyle.Render(m.APIURLInput.View()) + "\n")
+		formContent.WriteString(HintStyle.Render("leave empty for github.com") + "\n\n")
+		formContent.WriteString("Press Enter to continue, esc to cancel")
 	case models.TokenInput:
 		formContent.WriteString("GitHub Personal Access Token:\n")
-		formContent.WriteString(InputStyle.Render(m.TokenInput.View()) + "\n\n")
-		formContent.WriteString("Press Enter to save, esc to cancel")
+		formContent.WriteString(InputStyle.Render(m.TokenInput.View()) + "\n")
+		formContent.WriteString(HintStyle.Render(tokenRefHint) + "\n\n")
+		if m.Loading {
+			formContent.WriteString(fmt.Sprintf("%s Validating token...", m.Spinner.View()))
+		} else {
+			formContent.WriteString("Press Enter to save, esc to cancel")
+		}
+	}
+
+	// Сообщение
+	if m.Message != "" {
+		style := MessageStyle(m.MessageType)
+		formContent.WriteString("\n\n" + style.Render(m.Message))
+	}
+
+	// Центрируем всю форму по центру экрана
+	centeredForm := lipgloss.Place(
+		m.Width,
+		m.Height,
+		lipgloss.Center,
+		lipgloss.Center,
+		formContent.String(),
+	)
+	doc.WriteString(centeredForm)
+
+	return AppStyle.Render(doc.String())
+}
+
+// RenderUnlockScreen рендерит экран ввода парольной фразы
+func RenderUnlockScreen(m *AppModel) string {
+	doc := strings.Builder{}
+
+	formContent := strings.Builder{}
+	if m.ConfigManager.HasPassphrase() {
+		formContent.WriteString(FormTitleStyle.Render("Unlock Accounts") + "\n\n")
+		formContent.WriteString("Passphrase:\n")
+		formContent.WriteString(InputStyle.Render(m.PassphraseInput.View()) + "\n\n")
+		formContent.WriteString("Press Enter to unlock, esc to quit")
+	} else {
+		formContent.WriteString(FormTitleStyle.Render("Set Passphrase") + "\n\n")
+		formContent.WriteString("Tokens will be encrypted with this passphrase.\n\n")
+		switch m.FormState {
+		case models.PassphraseInput:
+			formContent.WriteString("New passphrase:\n")
+			formContent.WriteString(InputStyle.Render(m.PassphraseInput.View()) + "\n\n")
+			formContent.WriteString("Press Enter to continue, esc to quit")
+		case models.PassphraseConfirmInput:
+			formContent.WriteString("Repeat passphrase:\n")
+			formContent.WriteString(InputStyle.Render(m.ConfirmInput.View()) + "\n\n")
+			formContent.WriteString("Press Enter to save, esc to quit")
+		}
 	}
 
 	// Сообщение
 	if m.Message != "" {
-		var style lipgloss.Style
-		if m.MessageType == "success" {
-			style = SuccessStyle
+		style := MessageStyle(m.MessageType)
+		formContent.WriteString("\n\n" + style.Render(m.Message))
+	}
+
+	centeredForm := lipgloss.Place(
+		m.Width,
+		m.Height,
+		lipgloss.Center,
+		lipgloss.Center,
+		formContent.String(),
+	)
+	doc.WriteString(centeredForm)
+
+	return AppStyle.Render(doc.String())
+}
+
+// RenderConfirmDeleteScreen рендерит окно подтверждения удаления аккаунта
+func RenderConfirmDeleteScreen(m *AppModel) string {
+	account := m.Accounts[m.EditingAccount]
+
+	modalContent := strings.Builder{}
+	modalContent.WriteString(FormTitleStyle.Render("Delete Account") + "\n\n")
+	modalContent.WriteString(fmt.Sprintf("Delete account %s", account.Name))
+	if account.Login != "" {
+		modalContent.WriteString(fmt.Sprintf(" (@%s)", account.Login))
+	}
+	modalContent.WriteString("?\n")
+	modalContent.WriteString(HintStyle.Render("Cloned repositories will not be removed.") + "\n\n")
+	modalContent.WriteString("Press y to delete, n or esc to cancel")
+
+	centeredModal := lipgloss.Place(
+		m.Width,
+		m.Height,
+		lipgloss.Center,
+		lipgloss.Center,
+		ModalStyle.Render(modalContent.String()),
+	)
+	return AppStyle.Render(centeredModal)
+}
+
+// RenderEditAccountScreen рендерит форму переименования аккаунта или замены токена
+func RenderEditAccountScreen(m *AppModel) string {
+	doc := strings.Builder{}
+	account := m.Accounts[m.EditingAccount]
+
+	formContent := strings.Builder{}
+	switch m.State {
+	case models.StateRenameAccount:
+		formContent.WriteString(FormTitleStyle.Render(fmt.Sprintf("Rename Account %s", account.Name)) + "\n\n")
+		formContent.WriteString("Account Name:\n")
+		formContent.WriteString(InputStyle.Render(m.NameInput.View()) + "\n\n")
+		formContent.WriteString("Press Enter to save, esc to cancel")
+	case models.StateRotateToken:
+		formContent.WriteString(FormTitleStyle.Render(fmt.Sprintf("Rotate Token for %s", account.Name)) + "\n\n")
+		formContent.WriteString("New GitHub Personal Access Token:\n")
+		formContent.WriteString(InputStyle.Render(m.TokenInput.View()) + "\n")
+		formContent.WriteString(HintStyle.Render(tokenRefHint) + "\n\n")
+		if m.Loading {
+			formContent.WriteString(fmt.Sprintf("%s Validating token...", m.Spinner.View()))
 		} else {
-			style = ErrorStyle
+			formContent.WriteString("Press Enter to save, esc to cancel")
 		}
+	case models.StateAccountSettings:
+		formContent.WriteString(FormTitleStyle.Render(fmt.Sprintf("Settings for %s", account.Name)) + "\n\n")
+		for i, setting := range accountSettings {
+			formContent.WriteString(setting.label + ":\n")
+			formContent.WriteString(InputStyle.Render(m.SettingInputs[i].View()) + "\n")
+		}
+		formContent.WriteString(HintStyle.Render("leave a field empty to use the global git and ssh config") + "\n\n")
+		formContent.WriteString("Press Tab or ↑/↓ to switch fields, Enter to save, esc to cancel")
+	}
+
+	// Сообщение
+	if m.Message != "" {
+		style := MessageStyle(m.MessageType)
 		formContent.WriteString("\n\n" + style.Render(m.Message))
 	}
 
-	// Центрируем всю форму по центру экрана
 	centeredForm := lipgloss.Place(
 		m.Width,
 		m.Height,
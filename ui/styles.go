@@ -40,13 +40,42 @@ var (
 			BorderForeground(lipgloss.Color("240")).
 			Padding(0, utils.DefaultPadding)
 
+	ModalStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#FF0000")).
+			Padding(1, 2)
+
 	FormTitleStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#25A065")).
 			Bold(true)
 
+	HintStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("240")).
+			Italic(true)
+
 	SuccessStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#25A065"))
 
 	ErrorStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#FF0000"))
+
+	WarningStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFA500"))
+
+	ChipStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFFDF5")).
+			Background(lipgloss.Color("#25A065")).
+			Padding(0, 1)
 )
+
+// MessageStyle возвращает стиль сообщения по его типу
+func MessageStyle(messageType string) lipgloss.Style {
+	switch messageType {
+	case "success":
+		return SuccessStyle
+	case "warning":
+		return WarningStyle
+	default:
+		return ErrorStyle
+	}
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/KharpukhaevV/gitui/models"
+)
+
+// allAccountsTitle название общего списка репозиториев всех аккаунтов
+const allAccountsTitle = "All accounts"
+
+// hasAllAccounts сообщает, показывать ли общий список всех аккаунтов:
+// он нужен только при нескольких аккаунтах
+func (m *AppModel) hasAllAccounts() bool {
+	return len(m.Accounts) > 1
+}
+
+// accountFor возвращает аккаунт, через который выполняются операции с репозиторием.
+// В общем списке он выбирается среди аккаунтов с доступом к репозиторию.
+func (m *AppModel) accountFor(repo models.Repository) models.Account {
+	if !m.Unified {
+		return *m.SelectedAccountPtr
+	}
+	if account, ok := repo.AccountFor(m.Accounts); ok {
+		return account
+	}
+	return *m.SelectedAccountPtr
+}
+
+// reposByAccount группирует репозитории по аккаунтам, через которые с ними работать.
+// Группы возвращаются в порядке первого появления аккаунта в repos.
+func (m *AppModel) reposByAccount(repos []models.Repository) ([]models.Account, [][]models.Repository) {
+	var accounts []models.Account
+	var groups [][]models.Repository
+	index := map[string]int{}
+	for _, repo := range repos {
+		account := m.accountFor(repo)
+		i, ok := index[account.Name]
+		if !ok {
+			i = len(accounts)
+			index[account.Name] = i
+			accounts = append(accounts, account)
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], repo)
+	}
+	return accounts, groups
+}
+
+// singleAccountOnly сообщает, что действие недоступно в общем списке всех аккаунтов
+func (m *AppModel) singleAccountOnly(action string) bool {
+	if !m.Unified {
+		return false
+	}
+	m.Message = fmt.Sprintf("Open a single account to %s", action)
+	m.MessageType = "warning"
+	return true
+}
@@ -4,9 +4,15 @@ const (
 	// DefaultInputWidth стандартная ширина поля ввода
 	DefaultInputWidth = 40
 
+	// DefaultProgressWidth стандартная ширина индикатора прогресса
+	DefaultProgressWidth = 60
+
 	// MinInputWidth минимальная ширина поля ввода
 	MinInputWidth = 20
 
+	// FilterCharLimit максимальная длина запроса фильтра
+	FilterCharLimit = 256
+
 	// DefaultPadding стандартный отступ
 	DefaultPadding = 1
 
@@ -19,6 +25,9 @@ const (
 	// DefaultFileMode права доступа к файлу
 	DefaultFileMode = 0644
 
+	// SecretFileMode права доступа к файлу с секретами
+	SecretFileMode = 0600
+
 	// DefaultDirMode права доступа к директории
 	DefaultDirMode = 0755
 )
@@ -3,27 +3,23 @@ package utils
 import (
 	"os"
 	"path/filepath"
+	"strings"
 )
 
-// GetDevelopPath возвращает путь к директории develop
-func GetDevelopPath() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(home, "develop"), nil
+// DirExists проверяет существование директории
+func DirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
 }
 
-// CheckDevelopDir проверяет существование директории develop
-func CheckDevelopDir() (string, bool, error) {
-	devPath, err := GetDevelopPath()
-	if err != nil {
-		return "", false, err
+// ExpandHome заменяет ведущий ~ на домашнюю директорию пользователя
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
 	}
-
-	if _, err := os.Stat(devPath); os.IsNotExist(err) {
-		return devPath, false, nil
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
 	}
-
-	return devPath, true, nil
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
 }